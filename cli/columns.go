@@ -2,41 +2,64 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"go/token"
+	"io"
 	"strings"
 
-	"atomicgo.dev/keyboard"
 	"atomicgo.dev/keyboard/keys"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"go.uber.org/zap"
 
+	"github.com/FireAnomaly/go-generator-repository/config"
 	"github.com/FireAnomaly/go-generator-repository/model"
 )
 
 type columnWriter struct {
-	writer     *writer
-	userAction UserAction
-	db         *model.Database
-	logger     *zap.Logger
+	writer      *writer
+	userAction  UserAction
+	db          *model.Database
+	editor      lineEditor
+	input       KeySource
+	out         io.Writer
+	previewer   Previewer
+	showPreview bool
+	// query и onlyFailures фильтр строк. shownColumns и shownFailed номера строк, выведенных последним writeColumns.
+	query        string
+	onlyFailures bool
+	shownColumns []int
+	shownFailed  []int
+	logger       *zap.Logger
 }
 
-func newColumnWriter(db *model.Database, logger *zap.Logger) *columnWriter {
+var (
+	columnsHeader       = table.Row{"Original Name", "CamelCased Name", "Type", "DefaultValue", "EnumValues", "IsNull"}
+	failedColumnsHeader = table.Row{"Line", "Original Name", "Reason", "Source"}
+)
+
+// failedColumnsTitle заголовок таблицы неразобранных строк под таблицей колонок.
+const failedColumnsTitle = "Failed to parse (t - assign Go type to generate the column anyway):"
+
+func newColumnWriter(db *model.Database, logger *zap.Logger, options Options) *columnWriter {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
 	return &columnWriter{
-		writer: newWriter(minRows, len(db.Columns)),
-		db:     db,
-		logger: logger,
+		writer:      newWriter(minRows, len(db.Columns)+len(db.FailedParseColumns)),
+		db:          db,
+		input:       options.Input,
+		out:         options.Output,
+		previewer:   options.Previewer,
+		showPreview: options.Previewer != nil,
+		logger:      logger,
 	}
 }
 
 func (cw *columnWriter) manageColumns() (UserAction, error) {
 	cw.writeColumns()
 
-	if err := keyboard.Listen(cw.keyboardListenWrapperManageColumns); err != nil {
+	if err := cw.input.Listen(cw.keyboardListenWrapperManageColumns); err != nil {
 		return UserImmediatelyClose, err
 	}
 
@@ -45,6 +68,86 @@ func (cw *columnWriter) manageColumns() (UserAction, error) {
 }
 
 func (cw *columnWriter) keyboardListenWrapperManageColumns(key keys.Key) (stop bool, err error) {
+	if cw.editor.handleKey(key) {
+		// Правка могла изменить имя или перенести неразобранную строку в колонки.
+		if !cw.editor.active {
+			cw.applyFilter()
+		}
+
+		cw.writeColumns()
+
+		return false, nil
+	}
+
+	switch key.String() {
+	case "p":
+		cw.showPreview = !cw.showPreview && cw.previewer != nil
+		cw.writeColumns()
+
+		return false, nil
+
+	case "/":
+		cw.editor.startIncremental("Search", cw.query, func(query string) {
+			cw.query = query
+			cw.applyFilter()
+		})
+		cw.writeColumns()
+
+		return false, nil
+
+	case "f":
+		cw.onlyFailures = !cw.onlyFailures
+		cw.applyFilter()
+		cw.writeColumns()
+
+		return false, nil
+	}
+
+	column := cw.selectedColumn()
+	if column == nil {
+		failedIndex := cw.selectedFailedIndex()
+		if key.String() == "t" && failedIndex >= 0 && cw.db.FailedParseColumns[failedIndex].IsResolvable() {
+			failed := cw.db.FailedParseColumns[failedIndex]
+			cw.editor.start("Go type for unparsed "+failed.OriginalName, "", cw.resolveFailedColumn)
+			cw.writeColumns()
+
+			return false, nil
+		}
+
+		return cw.keyboardListenWrapperNavigation(key)
+	}
+
+	switch key.String() {
+	case "r":
+		column.IsDisable = false
+		cw.writeColumns()
+
+		return false, nil
+
+	case "e":
+		cw.editor.start("Go field name for "+column.OriginalName, column.CamelCaseName, cw.renameColumn)
+		cw.writeColumns()
+
+		return false, nil
+
+	case "t":
+		cw.editor.start("Go type for "+column.OriginalName, column.Type, cw.retypeColumn)
+		cw.writeColumns()
+
+		return false, nil
+	}
+
+	if key.Code == keys.Backspace {
+		column.IsDisable = true
+		cw.writeColumns()
+
+		return false, nil
+	}
+
+	return cw.keyboardListenWrapperNavigation(key)
+}
+
+func (cw *columnWriter) keyboardListenWrapperNavigation(key keys.Key) (stop bool, err error) {
 	switch key.Code {
 	case keys.Down:
 		cw.writer.downRow()
@@ -58,6 +161,30 @@ func (cw *columnWriter) keyboardListenWrapperManageColumns(key keys.Key) (stop b
 
 		return false, nil
 
+	case keys.PgUp:
+		cw.writer.pageUp(pageSize(cw.out, cw.screenLines()))
+		cw.writeColumns()
+
+		return false, nil
+
+	case keys.PgDown:
+		cw.writer.pageDown(pageSize(cw.out, cw.screenLines()))
+		cw.writeColumns()
+
+		return false, nil
+
+	case keys.Home:
+		cw.writer.home()
+		cw.writeColumns()
+
+		return false, nil
+
+	case keys.End:
+		cw.writer.end()
+		cw.writeColumns()
+
+		return false, nil
+
 	case keys.Left:
 		cw.userAction = UserWantBackToMainMenu
 		return true, nil
@@ -81,13 +208,22 @@ func (cw *columnWriter) keyboardListenWrapperManageColumns(key keys.Key) (stop b
 func (cw *columnWriter) writeColumns() {
 	cw.logger.Debug("Start writeColumns")
 
-	clearConsole()
+	clearConsole(cw.out)
 
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Original Name", "CamelCased Name", "Type", "DefaultValue", "EnumValues", "IsNull"})
+	t.AppendHeader(columnsHeader)
 
-	for _, column := range cw.db.Columns {
+	cw.shownColumns, cw.shownFailed = cw.shownColumns[:0], cw.shownFailed[:0]
+	for _, row := range cw.writer.window(pageSize(cw.out, cw.screenLines())) {
+		if row > len(cw.db.Columns) {
+			cw.shownFailed = append(cw.shownFailed, row)
+		} else {
+			cw.shownColumns = append(cw.shownColumns, row)
+		}
+	}
+
+	for _, row := range cw.shownColumns {
+		column := cw.db.Columns[row-1]
 		t.AppendRow(table.Row{
 			column.OriginalName,
 			column.CamelCaseName,
@@ -101,15 +237,197 @@ func (cw *columnWriter) writeColumns() {
 
 	t.SetRowPainter(cw.getRowPainter)
 
-	t.Render()
+	var rendered strings.Builder
+	rendered.WriteString(t.Render())
+
+	if len(cw.shownFailed) > 0 {
+		rendered.WriteString("\n" + failedColumnsTitle + "\n")
+		failedTable := table.NewWriter()
+		failedTable.AppendHeader(failedColumnsHeader)
+
+		for _, row := range cw.shownFailed {
+			failed := cw.db.FailedParseColumns[row-1-len(cw.db.Columns)]
+			failedTable.AppendRow(table.Row{failed.LineNumber, failed.OriginalName, failed.Reason, failed.Snippet})
+			failedTable.AppendSeparator()
+		}
 
-	fmt.Printf("Left Arrow - to back on main screen \nCTRL+C - exit \nEnter - Apply\n>>>")
+		failedTable.SetRowPainter(cw.getFailedRowPainter)
+		rendered.WriteString(failedTable.Render())
+	}
+
+	footer := cw.footer()
+	if cw.showPreview {
+		fmt.Fprintln(cw.out, sideBySide(rendered.String(), renderPreview(cw.previewer, cw.db), terminalWidth(cw.out),
+			terminalHeight(cw.out)-lineCount(footer)))
+	} else {
+		fmt.Fprintln(cw.out, rendered.String())
+	}
+
+	fmt.Fprint(cw.out, footer)
+}
+
+// screenLines линии экрана помимо строк: рамки и заголовки таблиц, статус и подсказка по клавишам.
+func (cw *columnWriter) screenLines() int {
+	lines := tableChromeLines(columnsHeader) + lineCount(cw.footer())
+	if len(cw.db.FailedParseColumns) > 0 {
+		lines += lineCount(failedColumnsTitle) + tableChromeLines(failedColumnsHeader)
+	}
+
+	return lines
+}
+
+// footer статус и подсказка по клавишам или строка ввода под таблицами.
+func (cw *columnWriter) footer() string {
+	if cw.editor.active {
+		return fmt.Sprintf("%s\n%s\n>>>", cw.status(), cw.editor.String())
+	}
+
+	return cw.status() + "\n" + "Left Arrow - to back on main screen \nCTRL+C - exit \nEnter - Apply \nBackspace - Disable Column\n" +
+		"r - To Restore Disabled\ne - Edit Go Field Name\nt - Override Go Type\n/ - Search\nf - Only Unparsed Lines\n" +
+		"PgUp/PgDown/Home/End - Scroll\np - Toggle Code Preview\n>>>"
+}
+
+func (cw *columnWriter) applyFilter() {
+	if cw.query == "" && !cw.onlyFailures {
+		cw.writer.setVisible(nil)
+
+		return
+	}
+
+	rows := make([]int, 0, cw.writer.MaxRows)
+	if !cw.onlyFailures {
+		for i, column := range cw.db.Columns {
+			if matchQuery(cw.query, column.OriginalName, column.CamelCaseName) {
+				rows = append(rows, i+minRows)
+			}
+		}
+	}
+
+	for i, failed := range cw.db.FailedParseColumns {
+		if matchQuery(cw.query, failed.OriginalName) {
+			rows = append(rows, len(cw.db.Columns)+i+minRows)
+		}
+	}
 
-	return
+	cw.writer.setVisible(rows)
+}
+
+func (cw *columnWriter) status() string {
+	shown := len(cw.shownColumns) + len(cw.shownFailed)
+	status := fmt.Sprintf("Rows %d-%d of %d", cw.writer.Offset+1, cw.writer.Offset+shown, len(cw.writer.rows()))
+	if shown == 0 {
+		status = "No rows"
+	}
+
+	if len(cw.writer.rows()) != cw.writer.MaxRows {
+		status += fmt.Sprintf(" (filtered from %d", cw.writer.MaxRows)
+		if cw.query != "" {
+			status += fmt.Sprintf(", search %q", cw.query)
+		}
+
+		if cw.onlyFailures {
+			status += ", unparsed lines only"
+		}
+
+		status += ")"
+	}
+
+	return status
+}
+
+// selectedColumn возвращает nil, если у таблицы нет колонок или выбрана строка, скрытая фильтром.
+func (cw *columnWriter) selectedColumn() *model.Column {
+	if !cw.writer.hasSelection() || cw.writer.SelectedRow-1 >= len(cw.db.Columns) {
+		return nil
+	}
+
+	return &cw.db.Columns[cw.writer.SelectedRow-1]
+}
+
+// selectedFailedIndex индекс выбранной неразобранной строки или -1, если выбрана обычная колонка.
+func (cw *columnWriter) selectedFailedIndex() int {
+	index := cw.writer.SelectedRow - 1 - len(cw.db.Columns)
+	if !cw.writer.hasSelection() || index < 0 || index >= len(cw.db.FailedParseColumns) {
+		return -1
+	}
+
+	return index
+}
+
+func (cw *columnWriter) resolveFailedColumn(goType string) error {
+	goType, err := validateGoType(goType)
+	if err != nil {
+		return err
+	}
+
+	column := cw.db.ResolveFailedColumn(cw.selectedFailedIndex(), goType)
+	cw.logger.Debug("Failed column resolved", zap.String("column", column.OriginalName), zap.String("type", goType))
+
+	return nil
+}
+
+func (cw *columnWriter) renameColumn(name string) error {
+	name = strings.TrimSpace(name)
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return fmt.Errorf("%q is not an exported Go identifier", name)
+	}
+
+	for i, column := range cw.db.Columns {
+		if i != cw.writer.SelectedRow-1 && !column.IsDisable && column.CamelCaseName == name {
+			return fmt.Errorf("field %s already exists", name)
+		}
+	}
+
+	cw.logger.Debug("Column renamed", zap.String("column", cw.selectedColumn().OriginalName), zap.String("name", name))
+	cw.selectedColumn().CamelCaseName = name
+
+	return nil
+}
+
+func (cw *columnWriter) retypeColumn(goType string) error {
+	goType, err := validateGoType(goType)
+	if err != nil {
+		return err
+	}
+
+	cw.logger.Debug("Column type overridden", zap.String("column", cw.selectedColumn().OriginalName), zap.String("type", goType))
+	cw.selectedColumn().Type = goType
+
+	return nil
+}
+
+func validateGoType(goType string) (string, error) {
+	goType = strings.TrimSpace(goType)
+	if goType == "" {
+		return "", fmt.Errorf("type is empty")
+	}
+
+	if err := config.ValidateGoType(goType); err != nil {
+		return "", err
+	}
+
+	return goType, nil
+}
+
+func (cw *columnWriter) getFailedRowPainter(row table.Row, attr table.RowAttributes) text.Colors {
+	if cw.shownFailed[attr.Number-1] == cw.writer.SelectedRow {
+		return text.Colors{text.FgGreen}
+	}
+
+	return text.Colors{text.FgYellow}
 }
 
 func (cw *columnWriter) getRowPainter(row table.Row, attr table.RowAttributes) text.Colors {
-	if attr.Number == cw.writer.SelectedRow {
+	number := cw.shownColumns[attr.Number-1]
+	if cw.db.Columns[number-1].IsDisable {
+		if number == cw.writer.SelectedRow {
+			return text.Colors{text.FgHiRed}
+		}
+
+		return text.Colors{text.FgRed}
+	}
+
+	if number == cw.writer.SelectedRow {
 		return text.Colors{text.FgGreen}
 	}
 
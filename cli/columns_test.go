@@ -0,0 +1,29 @@
+package cli
+
+import "testing"
+
+func TestValidateGoType(t *testing.T) {
+	tests := map[string]bool{
+		"int64":                   true,
+		" time.Time ":             true,
+		"*string":                 true,
+		"[]byte":                  true,
+		"[16]byte":                true,
+		"map[string]any":          true,
+		"sql.Null[string]":        true,
+		"json.RawMessage":         true,
+		"":                        false,
+		"1+2":                     false,
+		"f()":                     false,
+		"values[1]":               false,
+		"a.b.c":                   false,
+		"[n+1]byte":               false,
+		"func() int { return 1 }": false,
+	}
+
+	for goType, valid := range tests {
+		if _, err := validateGoType(goType); (err == nil) != valid {
+			t.Errorf("validateGoType(%q) error = %v, want valid %v", goType, err, valid)
+		}
+	}
+}
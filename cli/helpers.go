@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"io"
 	"os"
-	"os/exec"
-	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/term"
 )
 
 type writer struct {
@@ -11,6 +15,10 @@ type writer struct {
 	MinRows      int
 	DisabledRows map[int]bool
 	SelectedRow  int
+	// Visible номера строк, прошедших фильтр, по порядку. nil - видны все строки.
+	Visible []int
+	// Offset позиция первой строки на экране среди видимых.
+	Offset int
 }
 
 const minRows = 1 // really? :)
@@ -27,23 +35,87 @@ func newWriter(selectedRow int, countRows int) *writer {
 }
 
 func (w *writer) upRow() {
-	selectedRow := w.SelectedRow
-	selectedRow--
-	if w.MinRows > selectedRow {
-		return
+	w.moveTo(w.position() - 1)
+}
+
+func (w *writer) downRow() {
+	w.moveTo(w.position() + 1)
+}
+
+func (w *writer) pageUp(pageSize int) {
+	w.moveTo(max(w.position()-pageSize, 0))
+}
+
+func (w *writer) pageDown(pageSize int) {
+	w.moveTo(min(w.position()+pageSize, len(w.rows())-1))
+}
+
+func (w *writer) home() {
+	w.moveTo(0)
+}
+
+func (w *writer) end() {
+	w.moveTo(len(w.rows()) - 1)
+}
+
+// rows номера видимых строк с учётом фильтра.
+func (w *writer) rows() []int {
+	if w.Visible != nil {
+		return w.Visible
 	}
 
-	w.SelectedRow = selectedRow
+	rows := make([]int, 0, w.MaxRows)
+	for row := w.MinRows; row <= w.MaxRows; row++ {
+		rows = append(rows, row)
+	}
+
+	return rows
 }
 
-func (w *writer) downRow() {
-	selectedRow := w.SelectedRow
-	selectedRow++
-	if w.MaxRows < selectedRow {
+// position позиция выбранной строки среди видимых или -1, если она скрыта фильтром.
+func (w *writer) position() int {
+	return slices.Index(w.rows(), w.SelectedRow)
+}
+
+func (w *writer) hasSelection() bool {
+	return w.position() >= 0
+}
+
+func (w *writer) moveTo(position int) {
+	rows := w.rows()
+	if position < 0 || position >= len(rows) {
 		return
 	}
 
-	w.SelectedRow = selectedRow
+	w.SelectedRow = rows[position]
+}
+
+// setVisible применяет фильтр. Если выбранная строка скрыта, выбирается первая видимая.
+func (w *writer) setVisible(rows []int) {
+	w.Visible = rows
+	if !w.hasSelection() {
+		w.home()
+	}
+}
+
+// window возвращает строки, которые помещаются в height, сдвигая окно так, чтобы выбранная строка была видна.
+func (w *writer) window(height int) []int {
+	rows := w.rows()
+	height = max(height, 1)
+
+	if position := w.position(); position >= 0 {
+		if position < w.Offset {
+			w.Offset = position
+		}
+
+		if position >= w.Offset+height {
+			w.Offset = position - height + 1
+		}
+	}
+
+	w.Offset = max(min(w.Offset, len(rows)-height), 0)
+
+	return rows[w.Offset:min(w.Offset+height, len(rows))]
 }
 
 func (w *writer) addDeletedRow() {
@@ -56,17 +128,78 @@ func (w *writer) restoreRow() {
 	return
 }
 
-func clearConsole() {
-	switch runtime.GOOS {
-	case "windows":
-		cmd := exec.Command("cmd", "/c", "cls")
-		cmd.Stdout = os.Stdout
-		_ = cmd.Run()
-	case "linux", "darwin":
-		cmd := exec.Command("clear")
-		cmd.Stdout = os.Stdout
-		_ = cmd.Run()
-	default:
-		panic("Your platform is unsupported! I can't clearConsole terminal screen :(")
+// clearScreen переводит курсор в начало и очищает экран вместе с прокруткой.
+const clearScreen = "\x1b[H\x1b[2J\x1b[3J"
+
+func clearConsole(out io.Writer) {
+	_, _ = io.WriteString(out, clearScreen)
+}
+
+const (
+	defaultTerminalWidth  = 160
+	defaultTerminalHeight = 40
+)
+
+// terminalSize размер терминала, в который идёт вывод. Для вывода не в терминал (буфер, файл) берётся размер по
+// умолчанию, чтобы результат не зависел от окружения.
+func terminalSize(out io.Writer) (width, height int) {
+	file, ok := out.(*os.File)
+	if !ok {
+		return defaultTerminalWidth, defaultTerminalHeight
 	}
+
+	width, height, err := term.GetSize(int(file.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return defaultTerminalWidth, defaultTerminalHeight
+	}
+
+	return width, height
+}
+
+func terminalWidth(out io.Writer) int {
+	width, _ := terminalSize(out)
+
+	return width
+}
+
+func terminalHeight(out io.Writer) int {
+	_, height := terminalSize(out)
+
+	return height
+}
+
+// lineCount сколько линий экрана занимает text без учёта переноса длинных строк.
+func lineCount(text string) int {
+	return strings.Count(text, "\n") + 1
+}
+
+// tableChromeLines линии таблицы помимо строк: рамка и заголовок. Считается по таблице из одной строки,
+// которая вместе с разделителем занимает две линии, как и каждая следующая.
+func tableChromeLines(header table.Row) int {
+	t := table.NewWriter()
+	t.AppendHeader(header)
+	t.AppendRow(make(table.Row, len(header)))
+	t.AppendSeparator()
+
+	return lineCount(t.Render()) - 2
+}
+
+// pageSize сколько строк таблицы помещается на экран. Каждая строка занимает две линии вместе с разделителем,
+// reservedLines занимают заголовок таблицы, подсказка по клавишам и строка ввода.
+func pageSize(out io.Writer, reservedLines int) int {
+	_, height := terminalSize(out)
+
+	return max((height-reservedLines)/2, 1)
+}
+
+// matchQuery регистронезависимый поиск подстроки в любом из имён.
+func matchQuery(query string, names ...string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), query) {
+			return true
+		}
+	}
+
+	return false
 }
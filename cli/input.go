@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"atomicgo.dev/keyboard/keys"
+)
+
+// lineEditor однострочный ввод внутри экрана: новое имя поля, тип и т.п.
+// Пока редактор активен, он забирает себе все клавиши кроме CTRL+C.
+type lineEditor struct {
+	prompt  string
+	initial string
+	value   []rune
+	active  bool
+	err     error
+	onDone  func(value string) error
+	// onChange если задан, вызывается после каждого изменения значения, например для поиска по мере ввода.
+	// Esc в этом случае вызывает его с исходным значением.
+	onChange func(value string)
+}
+
+func (e *lineEditor) start(prompt, initial string, onDone func(value string) error) {
+	e.prompt = prompt
+	e.initial = initial
+	e.value = []rune(initial)
+	e.active = true
+	e.err = nil
+	e.onDone = onDone
+	e.onChange = nil
+}
+
+// startIncremental запускает ввод, результат которого применяется сразу при изменении.
+func (e *lineEditor) startIncremental(prompt, initial string, onChange func(value string)) {
+	e.start(prompt, initial, func(value string) error {
+		onChange(value)
+
+		return nil
+	})
+	e.onChange = onChange
+}
+
+// handleKey возвращает false, если клавишу должен обработать экран.
+func (e *lineEditor) handleKey(key keys.Key) bool {
+	if !e.active || key.Code == keys.CtrlC {
+		return false
+	}
+
+	switch key.Code {
+	case keys.Enter:
+		if e.err = e.onDone(string(e.value)); e.err == nil {
+			e.active = false
+		}
+	case keys.Escape:
+		e.active = false
+		e.value = []rune(e.initial)
+		e.changed()
+	case keys.Backspace, keys.CtrlH:
+		if len(e.value) > 0 {
+			e.value = e.value[:len(e.value)-1]
+			e.changed()
+		}
+	case keys.Space:
+		e.value = append(e.value, ' ')
+		e.changed()
+	case keys.RuneKey:
+		e.value = append(e.value, key.Runes...)
+		e.changed()
+	}
+
+	return true
+}
+
+func (e *lineEditor) changed() {
+	if e.onChange != nil {
+		e.onChange(string(e.value))
+	}
+}
+
+func (e *lineEditor) String() string {
+	if !e.active {
+		return ""
+	}
+
+	line := e.prompt + ": " + string(e.value) + "_  (Enter - save, Esc - cancel)"
+	if e.err != nil {
+		line += "\n" + e.err.Error()
+	}
+
+	return line
+}
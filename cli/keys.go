@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"errors"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+)
+
+var ErrScriptEnded = errors.New("scripted keys ended before the screen was closed")
+
+// KeySource источник нажатий клавиш. Listen передаёт клавиши в onKey, пока тот не вернёт stop или ошибку.
+type KeySource interface {
+	Listen(onKey func(key keys.Key) (stop bool, err error)) error
+}
+
+// KeyboardSource читает клавиши из терминала.
+type KeyboardSource struct{}
+
+func (KeyboardSource) Listen(onKey func(key keys.Key) (stop bool, err error)) error {
+	return keyboard.Listen(onKey)
+}
+
+// ScriptedKeys отдаёт заранее заданные клавиши. Позволяет прогнать целую интерактивную сессию без терминала,
+// например в тестах вместе с bytes.Buffer в Options.Output.
+type ScriptedKeys struct {
+	keys []keys.Key
+}
+
+func NewScriptedKeys(script ...keys.Key) *ScriptedKeys {
+	return &ScriptedKeys{keys: script}
+}
+
+// Listen продолжает с места, где остановился предыдущий экран. Если клавиши закончились раньше, чем экран
+// закрылся, возвращается ErrScriptEnded.
+func (s *ScriptedKeys) Listen(onKey func(key keys.Key) (stop bool, err error)) error {
+	for len(s.keys) > 0 {
+		key := s.keys[0]
+		s.keys = s.keys[1:]
+
+		stop, err := onKey(key)
+		if err != nil {
+			return err
+		}
+
+		if stop {
+			return nil
+		}
+	}
+
+	return ErrScriptEnded
+}
+
+// Key клавиша без символа: keys.Enter, keys.Down и т.п.
+func Key(code keys.KeyCode) keys.Key {
+	return keys.Key{Code: code}
+}
+
+// Text клавиши для ввода строки, например имени в поиске или нового типа колонки.
+func Text(value string) []keys.Key {
+	script := make([]keys.Key, 0, len(value))
+	for _, r := range value {
+		if r == ' ' {
+			script = append(script, keys.Key{Code: keys.Space, Runes: []rune{r}})
+
+			continue
+		}
+
+		script = append(script, keys.Key{Code: keys.RuneKey, Runes: []rune{r}})
+	}
+
+	return script
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"errors"
+	"io"
+	"os"
 
 	"go.uber.org/zap"
 
@@ -17,19 +19,39 @@ const (
 	UserWantBackToMainMenu
 )
 
+// Options дополнительные настройки TUI, нулевое значение допустимо.
+type Options struct {
+	// Previewer если задан, рядом с таблицами показывается код, который будет сгенерирован.
+	Previewer Previewer
+	// Input источник клавиш, по умолчанию терминал. Для сценариев без терминала - NewScriptedKeys.
+	Input KeySource
+	// Output куда выводятся экраны, по умолчанию os.Stdout.
+	Output io.Writer
+}
+
 type TableWriterOnCLI struct {
-	table  *tableWriter
-	logger *zap.Logger
+	table   *tableWriter
+	options Options
+	logger  *zap.Logger
 }
 
-func NewTableWriterOnCLI(logger *zap.Logger, dbs []*model.Database) *TableWriterOnCLI {
+func NewTableWriterOnCLI(logger *zap.Logger, dbs []*model.Database, options Options) *TableWriterOnCLI {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
+	if options.Input == nil {
+		options.Input = KeyboardSource{}
+	}
+
+	if options.Output == nil {
+		options.Output = os.Stdout
+	}
+
 	cli := &TableWriterOnCLI{
-		table:  newTableWriter(logger, dbs),
-		logger: logger.Named("CLI Table Writer: "),
+		table:   newTableWriter(logger, dbs, options),
+		options: options,
+		logger:  logger.Named("CLI Table Writer: "),
 	}
 
 	return cli
@@ -50,11 +72,11 @@ func (cli *TableWriterOnCLI) manageWriters() error {
 			return err
 		}
 
-		columnwriter := newColumnWriter(chosenDB, cli.logger)
+		columnwriter := newColumnWriter(chosenDB, cli.logger, cli.options)
 	choice:
 		switch userAction {
 		case UserImmediatelyClose:
-			clearConsole()
+			clearConsole(cli.options.Output)
 			return CloseSignal
 		case UserWantDiveToColumns:
 			userAction, err = columnwriter.manageColumns()
@@ -64,7 +86,7 @@ func (cli *TableWriterOnCLI) manageWriters() error {
 
 			goto choice
 		case UserAcceptChanges:
-			clearConsole()
+			clearConsole(cli.options.Output)
 			return nil
 		case UserWantBackToMainMenu:
 			break
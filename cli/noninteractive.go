@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// Selection выбор таблиц и колонок без TUI. Значения - шаблоны path.Match по оригинальным именам,
+// колонки задаются как "table.column" (например "*.password" для всех таблиц).
+type Selection struct {
+	Tables         []string
+	ExcludeTables  []string
+	ExcludeColumns []string
+}
+
+// NonInteractive применяет Selection вместо ручного выбора, нужен для CI и go:generate.
+type NonInteractive struct {
+	dbs       []*model.Database
+	selection Selection
+	logger    *zap.Logger
+}
+
+func NewNonInteractive(logger *zap.Logger, dbs []*model.Database, selection Selection) *NonInteractive {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &NonInteractive{dbs: dbs, selection: selection, logger: logger.Named("CLI Non Interactive: ")}
+}
+
+func (n *NonInteractive) ManageTableByUser() error {
+	n.logger.Debug("Start ManageTableByUser without user", zap.Any("selection", n.selection))
+
+	return ApplySelection(n.dbs, n.selection)
+}
+
+// ApplySelection отключает таблицы и колонки по Selection. Уже отключенные не включает обратно,
+// поэтому её можно применять поверх выбора из конфига.
+func ApplySelection(dbs []*model.Database, selection Selection) error {
+	for _, pattern := range selection.allPatterns() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, db := range dbs {
+		tableName := db.TableNames.Original
+		if len(selection.Tables) > 0 && !matchAny(selection.Tables, tableName) {
+			db.Disabled = true
+		}
+
+		if isExcluded(selection.ExcludeTables, tableName) {
+			db.Disabled = true
+		}
+
+		for i := range db.Columns {
+			if isExcluded(selection.ExcludeColumns, tableName+"."+db.Columns[i].OriginalName) {
+				db.Columns[i].IsDisable = true
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s Selection) allPatterns() []string {
+	patterns := append([]string{}, s.Tables...)
+	for _, pattern := range slices.Concat(s.ExcludeTables, s.ExcludeColumns) {
+		patterns = append(patterns, strings.TrimPrefix(pattern, "!"))
+	}
+
+	return patterns
+}
+
+// isExcluded шаблоны исключений применяются по порядку, "!name" возвращает исключённое ранее имя (как
+// config.IsExcluded).
+func isExcluded(patterns []string, name string) bool {
+	excluded := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if matched, _ := path.Match(strings.TrimPrefix(pattern, "!"), name); matched {
+			excluded = !negated
+		}
+	}
+
+	return excluded
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// Previewer показывает код, который будет сгенерирован для таблицы с текущими правками.
+type Previewer interface {
+	Preview(db *model.Database) (string, error)
+}
+
+// PreviewFunc позволяет передать функцию как Previewer.
+type PreviewFunc func(db *model.Database) (string, error)
+
+func (f PreviewFunc) Preview(db *model.Database) (string, error) {
+	return f(db)
+}
+
+const previewSeparator = " │ "
+
+// renderPreview возвращает код для правой панели или текст ошибки, чтобы она была видна прямо в TUI.
+func renderPreview(previewer Previewer, db *model.Database) string {
+	if previewer == nil || db == nil {
+		return ""
+	}
+
+	code, err := previewer.Preview(db)
+	if err != nil {
+		return "Preview failed: " + err.Error()
+	}
+
+	return code
+}
+
+// previewClipped последняя линия правой панели, если код не поместился по высоте.
+const previewClipped = "…"
+
+// sideBySide выводит left и right рядом. Правая панель обрезается по ширине терминала и по height линиям,
+// чтобы экран не прокручивался.
+func sideBySide(left, right string, width, height int) string {
+	leftLines := strings.Split(strings.TrimRight(left, "\n"), "\n")
+	rightLines := strings.Split(strings.TrimRight(strings.ReplaceAll(right, "\t", "    "), "\n"), "\n")
+	if height = max(height, len(leftLines), 1); len(rightLines) > height {
+		rightLines = append(rightLines[:height-1], previewClipped)
+	}
+
+	leftWidth := 0
+	for _, line := range leftLines {
+		leftWidth = max(leftWidth, text.StringWidthWithoutEscSequences(line))
+	}
+
+	rightWidth := width - leftWidth - text.StringWidthWithoutEscSequences(previewSeparator)
+
+	var builder strings.Builder
+	for i := range max(len(leftLines), len(rightLines)) {
+		var leftLine, rightLine string
+		if i < len(leftLines) {
+			leftLine = leftLines[i]
+		}
+
+		if i < len(rightLines) && rightWidth > 0 {
+			rightLine = text.Trim(rightLines[i], rightWidth)
+		}
+
+		padding := leftWidth - text.StringWidthWithoutEscSequences(leftLine)
+		builder.WriteString(leftLine + strings.Repeat(" ", padding) + previewSeparator + rightLine + "\n")
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
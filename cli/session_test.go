@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"atomicgo.dev/keyboard/keys"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func testDatabases() []*model.Database {
+	return []*model.Database{
+		{
+			TableNames: model.TableNames{Original: "users", CamelCase: "Users"},
+			Columns: []model.Column{
+				{OriginalName: "id", CamelCaseName: "ID", Type: "int", IsPrimaryKey: true},
+				{OriginalName: "email", CamelCaseName: "Email", Type: "string", IsNull: true},
+			},
+		},
+		{
+			TableNames: model.TableNames{Original: "orders", CamelCase: "Orders"},
+			Columns: []model.Column{
+				{OriginalName: "id", CamelCaseName: "ID", Type: "int", IsPrimaryKey: true},
+				{OriginalName: "user_id", CamelCaseName: "UserID", Type: "int"},
+				{OriginalName: "note", CamelCaseName: "Note", Type: "string", IsNull: true},
+			},
+		},
+	}
+}
+
+func TestScriptedSession(t *testing.T) {
+	script := slices.Concat(
+		[]keys.Key{Key(keys.Down), Key(keys.Right), Key(keys.Down), {Code: keys.RuneKey, Runes: []rune{'e'}}},
+		[]keys.Key{Key(keys.Backspace), Key(keys.Backspace)},
+		Text("Customer"),
+		[]keys.Key{Key(keys.Enter), Key(keys.Down), Key(keys.Backspace), Key(keys.Left), Key(keys.Enter)},
+	)
+
+	var output bytes.Buffer
+	dbs := testDatabases()
+	err := NewTableWriterOnCLI(nil, dbs, Options{Input: NewScriptedKeys(script...), Output: &output}).ManageTableByUser()
+	if err != nil {
+		t.Fatalf("ManageTableByUser() error: %v", err)
+	}
+
+	orders := dbs[1]
+	if got := orders.Columns[1].CamelCaseName; got != "UserCustomer" {
+		t.Errorf("orders.user_id Go name = %q, want %q", got, "UserCustomer")
+	}
+
+	if !orders.Columns[2].IsDisable {
+		t.Error("orders.note is not disabled")
+	}
+
+	assertGolden(t, "session.golden", output.Bytes())
+}
+
+func TestScriptedKeysEnded(t *testing.T) {
+	err := NewTableWriterOnCLI(nil, testDatabases(), Options{
+		Input:  NewScriptedKeys(Key(keys.Down)),
+		Output: &bytes.Buffer{},
+	}).ManageTableByUser()
+	if err != ErrScriptEnded {
+		t.Errorf("ManageTableByUser() error = %v, want %v", err, ErrScriptEnded)
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test ./cli -update to rewrite it", path)
+	}
+}
+
+func TestScreenFitsTerminal(t *testing.T) {
+	dbs := make([]*model.Database, 0, 50)
+	for i := range 50 {
+		db := testDatabases()[i%2]
+		db.Columns = slices.Repeat(db.Columns, 20)
+		db.FailedParseColumns = []model.FailedParsedColumn{{OriginalName: "geo", LineNumber: 3, Snippet: "geo POINT"}}
+		dbs = append(dbs, db)
+	}
+
+	var output bytes.Buffer
+	script := []keys.Key{Key(keys.PgDown), Key(keys.Right), Key(keys.PgDown), Key(keys.End), Key(keys.Enter)}
+	err := NewTableWriterOnCLI(nil, dbs, Options{Input: NewScriptedKeys(script...), Output: &output}).ManageTableByUser()
+	if err != nil {
+		t.Fatalf("ManageTableByUser() error: %v", err)
+	}
+
+	screens := strings.Split(output.String(), "\x1b[H\x1b[2J\x1b[3J")
+	for i, screen := range screens[1 : len(screens)-1] {
+		if lines := lineCount(screen); lines > defaultTerminalHeight {
+			t.Errorf("screen %d takes %d lines, terminal has %d", i+1, lines, defaultTerminalHeight)
+		}
+	}
+}
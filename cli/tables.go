@@ -2,9 +2,10 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"path"
+	"strings"
 
-	"atomicgo.dev/keyboard"
 	"atomicgo.dev/keyboard/keys"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -14,28 +15,52 @@ import (
 )
 
 type tableWriter struct {
-	logger     *zap.Logger
-	dbs        []*model.Database
-	userAction UserAction
-	writer     *writer
+	logger      *zap.Logger
+	dbs         []*model.Database
+	userAction  UserAction
+	writer      *writer
+	input       KeySource
+	out         io.Writer
+	previewer   Previewer
+	showPreview bool
+	editor      lineEditor
+	// query и onlyFailures фильтр списка таблиц, shown строки, выведенные на экран последним writeTable.
+	query        string
+	onlyFailures bool
+	shown        []int
 }
 
-func newTableWriter(logger *zap.Logger, dbs []*model.Database) *tableWriter {
+var tablesHeader = table.Row{"Original Name", "CamelCased Name", "Line Number", "Failed Columns"}
+
+func newTableWriter(logger *zap.Logger, dbs []*model.Database, options Options) *tableWriter {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
-	return &tableWriter{
-		logger: logger,
-		dbs:    dbs,
-		writer: newWriter(minRows, len(dbs)),
+	tw := &tableWriter{
+		logger:      logger,
+		dbs:         dbs,
+		writer:      newWriter(minRows, len(dbs)),
+		input:       options.Input,
+		out:         options.Output,
+		previewer:   options.Previewer,
+		showPreview: options.Previewer != nil,
+	}
+
+	// Таблицы могли быть отключены заранее, например сохранённым в конфиге выбором.
+	for i, db := range dbs {
+		if db.Disabled {
+			tw.writer.DisabledRows[i+minRows] = true
+		}
 	}
+
+	return tw
 }
 
 func (tw *tableWriter) manageDBs() (UserAction, *model.Database, error) {
 	tw.writeTable()
 
-	err := keyboard.Listen(tw.keyboardListenWrapperManageDBs)
+	err := tw.input.Listen(tw.keyboardListenWrapperManageDBs)
 	if err != nil {
 		return UserImmediatelyClose, nil, err
 	}
@@ -44,9 +69,64 @@ func (tw *tableWriter) manageDBs() (UserAction, *model.Database, error) {
 }
 
 func (tw *tableWriter) keyboardListenWrapperManageDBs(key keys.Key) (stop bool, err error) {
-	if key.String() == "r" {
+	if tw.editor.handleKey(key) {
+		tw.writeTable()
+
+		return false, nil
+	}
+
+	switch key.String() {
+	case "r":
 		tw.enableDB()
 		tw.writeTable()
+
+		return false, nil
+
+	case "/":
+		tw.editor.startIncremental("Search", tw.query, func(query string) {
+			tw.query = query
+			tw.applyFilter()
+		})
+		tw.writeTable()
+
+		return false, nil
+
+	case "f":
+		tw.onlyFailures = !tw.onlyFailures
+		tw.applyFilter()
+		tw.writeTable()
+
+		return false, nil
+
+	case "a":
+		tw.setVisibleDisabled(func(bool) bool { return false })
+		tw.writeTable()
+
+		return false, nil
+
+	case "d":
+		tw.setVisibleDisabled(func(bool) bool { return true })
+		tw.writeTable()
+
+		return false, nil
+
+	case "i":
+		tw.setVisibleDisabled(func(disabled bool) bool { return !disabled })
+		tw.writeTable()
+
+		return false, nil
+
+	case "s":
+		tw.editor.start("Select tables matching (example: audit_*)", "", tw.selectByPattern)
+		tw.writeTable()
+
+		return false, nil
+
+	case "p":
+		tw.showPreview = !tw.showPreview && tw.previewer != nil
+		tw.writeTable()
+
+		return false, nil
 	}
 
 	switch key.Code {
@@ -62,7 +142,35 @@ func (tw *tableWriter) keyboardListenWrapperManageDBs(key keys.Key) (stop bool,
 
 		return false, nil
 
+	case keys.PgUp:
+		tw.writer.pageUp(pageSize(tw.out, tw.screenLines()))
+		tw.writeTable()
+
+		return false, nil
+
+	case keys.PgDown:
+		tw.writer.pageDown(pageSize(tw.out, tw.screenLines()))
+		tw.writeTable()
+
+		return false, nil
+
+	case keys.Home:
+		tw.writer.home()
+		tw.writeTable()
+
+		return false, nil
+
+	case keys.End:
+		tw.writer.end()
+		tw.writeTable()
+
+		return false, nil
+
 	case keys.Right:
+		if !tw.writer.hasSelection() {
+			return false, nil
+		}
+
 		tw.userAction = UserWantDiveToColumns
 
 		return true, nil
@@ -90,46 +198,173 @@ func (tw *tableWriter) keyboardListenWrapperManageDBs(key keys.Key) (stop bool,
 }
 
 func (tw *tableWriter) disableDB() {
+	if !tw.writer.hasSelection() {
+		return
+	}
+
 	tw.dbs[tw.writer.SelectedRow-1].Disabled = true
 	tw.writer.addDeletedRow()
 }
 
 func (tw *tableWriter) enableDB() {
+	if !tw.writer.hasSelection() {
+		return
+	}
+
 	tw.dbs[tw.writer.SelectedRow-1].Disabled = false
 	tw.writer.restoreRow()
 }
 
+func (tw *tableWriter) setDisabled(row int, disabled bool) {
+	tw.dbs[row-1].Disabled = disabled
+	if disabled {
+		tw.writer.DisabledRows[row] = true
+	} else {
+		delete(tw.writer.DisabledRows, row)
+	}
+}
+
+// setVisibleDisabled меняет выбор всех таблиц, прошедших фильтр: toggle получает текущее состояние и возвращает новое.
+func (tw *tableWriter) setVisibleDisabled(toggle func(disabled bool) bool) {
+	for _, row := range tw.writer.rows() {
+		tw.setDisabled(row, toggle(tw.dbs[row-1].Disabled))
+	}
+}
+
+// selectByPattern включает таблицы, имя которых подходит под шаблон path.Match. Остальные не меняются.
+func (tw *tableWriter) selectByPattern(pattern string) error {
+	pattern = strings.TrimSpace(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	var selected int
+	for _, row := range tw.writer.rows() {
+		if matched, _ := path.Match(pattern, tw.dbs[row-1].TableNames.Original); matched {
+			tw.setDisabled(row, false)
+			selected++
+		}
+	}
+
+	if selected == 0 {
+		return fmt.Errorf("no tables match %q", pattern)
+	}
+
+	tw.logger.Debug("Tables selected by pattern", zap.String("pattern", pattern), zap.Int("count", selected))
+
+	return nil
+}
+
+func (tw *tableWriter) applyFilter() {
+	if tw.query == "" && !tw.onlyFailures {
+		tw.writer.setVisible(nil)
+
+		return
+	}
+
+	rows := make([]int, 0, len(tw.dbs))
+	for i, db := range tw.dbs {
+		if tw.onlyFailures && len(db.FailedParseColumns) == 0 {
+			continue
+		}
+
+		if matchQuery(tw.query, db.TableNames.Original, db.TableNames.CamelCase) {
+			rows = append(rows, i+minRows)
+		}
+	}
+
+	tw.writer.setVisible(rows)
+}
+
+// selectedDB возвращает nil, если фильтр скрыл все таблицы.
+func (tw *tableWriter) selectedDB() *model.Database {
+	if !tw.writer.hasSelection() {
+		return nil
+	}
+
+	return tw.dbs[tw.writer.SelectedRow-1]
+}
+
+func (tw *tableWriter) status() string {
+	status := fmt.Sprintf("Tables %d-%d of %d", tw.writer.Offset+1, tw.writer.Offset+len(tw.shown), len(tw.writer.rows()))
+	if len(tw.shown) == 0 {
+		status = "No tables"
+	}
+
+	if len(tw.writer.rows()) != len(tw.dbs) {
+		status += fmt.Sprintf(" (filtered from %d", len(tw.dbs))
+		if tw.query != "" {
+			status += fmt.Sprintf(", search %q", tw.query)
+		}
+
+		if tw.onlyFailures {
+			status += ", with failures only"
+		}
+
+		status += ")"
+	}
+
+	return status + fmt.Sprintf(" | Enabled %d, Disabled %d", len(tw.dbs)-len(tw.writer.DisabledRows), len(tw.writer.DisabledRows))
+}
+
 func (tw *tableWriter) writeTable() {
 	tw.logger.Debug("Start writeTable")
 
-	clearConsole()
+	clearConsole(tw.out)
 
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Original Name", "CamelCased Name", "Line Number"})
+	t.AppendHeader(tablesHeader)
 
-	for _, db := range tw.dbs {
-		t.AppendRow(table.Row{db.TableNames.Original, db.TableNames.CamelCase, len(db.Columns)})
+	tw.shown = tw.writer.window(pageSize(tw.out, tw.screenLines()))
+	for _, row := range tw.shown {
+		db := tw.dbs[row-1]
+		t.AppendRow(table.Row{db.TableNames.Original, db.TableNames.CamelCase, len(db.Columns), len(db.FailedParseColumns)})
 		t.AppendSeparator()
 	}
 
 	t.SetRowPainter(tw.getRowPainter)
 
-	t.Render()
+	footer := tw.footer()
+	rendered := t.Render()
+	if tw.showPreview {
+		rendered = sideBySide(rendered, renderPreview(tw.previewer, tw.selectedDB()), terminalWidth(tw.out),
+			terminalHeight(tw.out)-lineCount(footer))
+	}
+
+	fmt.Fprintln(tw.out, rendered)
+	fmt.Fprint(tw.out, footer)
+}
+
+// screenLines линии экрана помимо строк: рамка и заголовок таблицы, статус и подсказка по клавишам.
+func (tw *tableWriter) screenLines() int {
+	return tableChromeLines(tablesHeader) + lineCount(tw.footer())
+}
 
-	fmt.Printf("Right Arrow - Dive to selected base \nCTRL+C - Exit \nEnter - Apply \nBackspace - Disable Database\nr - To Restore Disabled\n>>>")
+// footer статус и подсказка по клавишам или строка ввода под таблицей.
+func (tw *tableWriter) footer() string {
+	if tw.editor.active {
+		return fmt.Sprintf("%s\n%s\n>>>", tw.status(), tw.editor.String())
+	}
 
-	return
+	return tw.status() + "\n" + "Right Arrow - Dive to selected base \nCTRL+C - Exit \nEnter - Apply \nBackspace - Disable Database\nr - To Restore Disabled\n" +
+		"a / d / i - Select All / Deselect All / Invert (filtered tables only)\ns - Select By Pattern\n" +
+		"/ - Search \nf - Only Tables With Failures\nPgUp/PgDown/Home/End - Scroll\np - Toggle Code Preview\n>>>"
 }
 
 func (tw *tableWriter) getRowPainter(row table.Row, attr table.RowAttributes) text.Colors {
-	if tw.writer.DisabledRows[attr.Number] {
+	number := tw.shown[attr.Number-1]
+	if tw.writer.DisabledRows[number] {
 		return text.Colors{text.FgRed}
 	}
 
-	if attr.Number == tw.writer.SelectedRow {
+	if number == tw.writer.SelectedRow {
 		return text.Colors{text.FgGreen}
 	}
 
+	// Таблицы с неразобранными колонками подсвечиваются, их стоит открыть и проверить.
+	if len(tw.dbs[number-1].FailedParseColumns) > 0 {
+		return text.Colors{text.FgYellow}
+	}
+
 	return text.Colors{text.FgWhite}
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"cmp"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/naming"
+)
+
+var reSpaces = regexp.MustCompile(`\s+`)
+
+// Apply применяет к разобранным таблицам правила именования и переопределения типов.
+// Выбор таблиц (Tables, Exclude) применяется отдельно через cli.ApplySelection.
+func (c *Config) Apply(databases []*model.Database) {
+	// Более длинные шаблоны конкретнее, поэтому применяются последними и перекрывают общие.
+	columnPatterns := slices.Collect(maps.Keys(c.Types.Columns))
+	slices.SortFunc(columnPatterns, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+
+	columnTypeOverride := func(columnKey string) (goType string, ok bool) {
+		for _, pattern := range columnPatterns {
+			if matched, _ := path.Match(pattern, columnKey); matched {
+				goType, ok = c.Types.Columns[pattern], true
+			}
+		}
+
+		return goType, ok
+	}
+
+	for _, db := range databases {
+		tableName := db.TableNames.Original
+
+		db.TableNames.CamelCase = naming.ApplyInitialisms(db.TableNames.CamelCase, c.Naming.Initialisms)
+		if goName, ok := c.Naming.Tables[tableName]; ok {
+			db.TableNames.CamelCase = goName
+		}
+
+		// Строки, которые парсер не разобрал, генерируются, если тип для колонки задан явно.
+		for i := len(db.FailedParseColumns) - 1; i >= 0; i-- {
+			failed := db.FailedParseColumns[i]
+			if goType, ok := columnTypeOverride(tableName + "." + failed.OriginalName); ok && failed.IsResolvable() {
+				db.ResolveFailedColumn(i, goType)
+			}
+		}
+
+		for i := range db.Columns {
+			column := &db.Columns[i]
+			columnKey := tableName + "." + column.OriginalName
+
+			column.CamelCaseName = naming.ApplyInitialisms(column.CamelCaseName, c.Naming.Initialisms)
+			if goName, ok := c.Naming.Columns[columnKey]; ok {
+				column.CamelCaseName = goName
+			}
+
+			if goType, ok := c.sqlTypeOverride(column.SQLType); ok {
+				column.Type = goType
+			}
+
+			if goType, ok := columnTypeOverride(columnKey); ok {
+				column.Type = goType
+			}
+		}
+	}
+}
+
+// sqlTypeOverride ищет тип по полному SQL типу ("tinyint(1)"), а затем по имени без аргументов ("decimal").
+func (c *Config) sqlTypeOverride(sqlType string) (string, bool) {
+	if sqlType == "" {
+		return "", false
+	}
+
+	normalized := normalizeSQLType(sqlType)
+	baseType, _, _ := strings.Cut(normalized, "(")
+
+	var (
+		baseGoType string
+		hasBase    bool
+	)
+	for key, goType := range c.Types.SQL {
+		switch normalizeSQLType(key) {
+		case normalized:
+			return goType, true
+		case strings.TrimSpace(baseType):
+			baseGoType, hasBase = goType, true
+		}
+	}
+
+	return baseGoType, hasBase
+}
+
+// normalizeSQLType приводит SQL тип к виду ключей Types.SQL: "TINYINT (1)" -> "tinyint(1)".
+func normalizeSQLType(sqlType string) string {
+	sqlType = strings.ToLower(reSpaces.ReplaceAllString(strings.TrimSpace(sqlType), " "))
+
+	return strings.ReplaceAll(strings.ReplaceAll(sqlType, " (", "("), ", ", ",")
+}
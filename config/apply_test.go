@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func TestApplyColumnTypePatterns(t *testing.T) {
+	cfg := &Config{Types: Types{Columns: map[string]string{
+		"legacy_*.payload":      "json.RawMessage",
+		"legacy_orders.payload": "[]byte",
+	}}}
+
+	databases := []*model.Database{
+		{
+			TableNames: model.TableNames{Original: "legacy_users"},
+			Columns:    []model.Column{{OriginalName: "payload", Type: "string", LineNumber: 3}},
+		},
+		{
+			TableNames: model.TableNames{Original: "legacy_events"},
+			FailedParseColumns: []model.FailedParsedColumn{
+				{OriginalName: "payload", CamelCaseName: "Payload", LineNumber: 3, Snippet: "payload GEOMETRY NOT NULL"},
+			},
+		},
+		{
+			TableNames: model.TableNames{Original: "legacy_orders"},
+			FailedParseColumns: []model.FailedParsedColumn{
+				{OriginalName: "payload", CamelCaseName: "Payload", LineNumber: 3, Snippet: "payload GEOMETRY"},
+			},
+		},
+	}
+
+	cfg.Apply(databases)
+
+	want := map[string]string{"legacy_users": "json.RawMessage", "legacy_events": "json.RawMessage", "legacy_orders": "[]byte"}
+	for _, db := range databases {
+		if len(db.Columns) != 1 || len(db.FailedParseColumns) != 0 {
+			t.Errorf("%s: columns = %v, failed = %v, want the payload column resolved",
+				db.TableNames.Original, db.Columns, db.FailedParseColumns)
+
+			continue
+		}
+
+		if got := db.Columns[0].Type; got != want[db.TableNames.Original] {
+			t.Errorf("%s.payload type = %q, want %q", db.TableNames.Original, got, want[db.TableNames.Original])
+		}
+	}
+}
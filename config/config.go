@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FireAnomaly/go-generator-repository/plugin"
+)
+
+// FileNames имена файла конфигурации в порядке поиска.
+var FileNames = []string{"gen.yaml", "gen.yml"}
+
+var (
+	ErrConfigNotFound     = errors.New("config file not found")
+	ErrUnsupportedDialect = errors.New("unsupported dialect")
+)
+
+// Config описывает весь запуск генератора. Пути задаются относительно директории файла конфигурации.
+type Config struct {
+	Input   string `yaml:"input,omitempty"`
+	Dialect string `yaml:"dialect,omitempty"`
+	Output  Output `yaml:"output,omitempty"`
+	Types   Types  `yaml:"types,omitempty"`
+	Tags    Tags   `yaml:"tags,omitempty"`
+	// Tables шаблоны таблиц для генерации, пустой список - все таблицы.
+	Tables     []string `yaml:"tables,omitempty"`
+	Exclude    Exclude  `yaml:"exclude,omitempty"`
+	Naming     Naming   `yaml:"naming,omitempty"`
+	Generators []string `yaml:"generators,omitempty"`
+	// Templates директория пользовательских шаблонов.
+	Templates string          `yaml:"templates,omitempty"`
+	Plugins   []plugin.Plugin `yaml:"plugins,omitempty"`
+	// Interactive false отключает TUI так же, как флаг -non-interactive.
+	Interactive *bool `yaml:"interactive,omitempty"`
+	Log         Log   `yaml:"log,omitempty"`
+
+	path string
+}
+
+type Output struct {
+	Path    string `yaml:"path,omitempty"`
+	Package string `yaml:"package,omitempty"`
+	// ImportPath Go путь импорта пакета в Path, например github.com/acme/app/internal/models.
+	ImportPath string `yaml:"import_path,omitempty"`
+	// Layout раскладка по пакетам: flat, schema или directory.
+	Layout string `yaml:"layout,omitempty"`
+	// FileName шаблон имени файла модели, например "{{snake .ModelName}}.go" или "models.go" для одного файла.
+	FileName string `yaml:"file_name,omitempty"`
+}
+
+// Types переопределяет Go типы: по SQL типу ("tinyint(1)": bool) или по колонке ("users.id": int64).
+type Types struct {
+	SQL     map[string]string `yaml:"sql,omitempty"`
+	Columns map[string]string `yaml:"columns,omitempty"`
+}
+
+type Tags struct {
+	// DB false отключает тег db, по умолчанию он включен.
+	DB *bool `yaml:"db,omitempty"`
+	// JSON стиль имени в теге json: none, original, snake или camel.
+	JSON string `yaml:"json,omitempty"`
+}
+
+type Exclude struct {
+	Tables  []string `yaml:"tables,omitempty"`
+	Columns []string `yaml:"columns,omitempty"`
+}
+
+type Naming struct {
+	Initialisms []string `yaml:"initialisms,omitempty"`
+	// Tables и Columns задают Go имена: "users": Account, "users.email": EmailAddress.
+	Tables  map[string]string `yaml:"tables,omitempty"`
+	Columns map[string]string `yaml:"columns,omitempty"`
+}
+
+type Log struct {
+	Enabled bool   `yaml:"enabled,omitempty"`
+	Level   string `yaml:"level,omitempty"`
+}
+
+// Load читает конфигурацию из файла.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err = yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config.path = path
+	if err = config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Discover ищет файл конфигурации в директории и её родителях до корня проекта: директории с go.mod или .git.
+// Вне проекта файл ищется только в самой директории, чтобы не подхватить случайный файл выше по дереву.
+func Discover(dir string) (*Config, error) {
+	dirs := []string{dir}
+	for current := dir; !isProjectRoot(current); {
+		parent := filepath.Dir(current)
+		if parent == current {
+			dirs = dirs[:1]
+
+			break
+		}
+
+		current = parent
+		dirs = append(dirs, current)
+	}
+
+	for _, dir := range dirs {
+		for _, name := range FileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return Load(path)
+			}
+		}
+	}
+
+	return nil, ErrConfigNotFound
+}
+
+// projectRootMarkers файлы, по которым Discover находит корень проекта.
+var projectRootMarkers = []string{"go.mod", ".git"}
+
+func isProjectRoot(dir string) bool {
+	for _, marker := range projectRootMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) Validate() error {
+	if c.Dialect != "" && c.Dialect != "mysql" {
+		return fmt.Errorf("%w: %s", ErrUnsupportedDialect, c.Dialect)
+	}
+
+	for _, p := range c.Plugins {
+		if p.Name == "" || len(p.Command) == 0 {
+			return fmt.Errorf("plugin requires name and command: %+v", p)
+		}
+	}
+
+	if err := validateGoTypes("types.sql", c.Types.SQL); err != nil {
+		return err
+	}
+
+	if err := validateGoTypes("types.columns", c.Types.Columns); err != nil {
+		return err
+	}
+
+	switch c.Tags.JSON {
+	case "", "none", "original", "snake", "camel":
+	default:
+		return fmt.Errorf("unknown json tag style: %s", c.Tags.JSON)
+	}
+
+	switch c.Output.Layout {
+	case "", "flat", "schema", "directory":
+	default:
+		return fmt.Errorf("unknown output layout: %s", c.Output.Layout)
+	}
+
+	return nil
+}
+
+// validateGoTypes проверяет переопределения типов так же, как TUI проверяет введённый тип.
+func validateGoTypes(section string, types map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(types)) {
+		if err := ValidateGoType(types[key]); err != nil {
+			return fmt.Errorf("%s %q: %w", section, key, err)
+		}
+	}
+
+	return nil
+}
+
+// ResolvedPlugins плагины с путями к программам относительно директории файла конфигурации.
+// Команды без разделителя пути ("gen-ts") ищутся в PATH как есть.
+func (c *Config) ResolvedPlugins() []plugin.Plugin {
+	plugins := make([]plugin.Plugin, 0, len(c.Plugins))
+	for _, p := range c.Plugins {
+		if len(p.Command) > 0 && strings.ContainsAny(p.Command[0], `/\`) {
+			p.Command = append([]string{c.ResolvePath(p.Command[0])}, p.Command[1:]...)
+		}
+
+		plugins = append(plugins, p)
+	}
+
+	return plugins
+}
+
+// Path путь к файлу, из которого загружена конфигурация. Пустой, если конфигурация не загружалась.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// ResolvePath приводит путь из конфигурации к абсолютному относительно директории файла конфигурации.
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c.path == "" {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(c.path), path)
+}
+
+func (c *Config) IsInteractive() bool {
+	return c.Interactive == nil || *c.Interactive
+}
+
+func (c *Config) IsDBTagEnabled() bool {
+	return c.Tags.DB == nil || *c.Tags.DB
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverStopsAtProjectRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "gen.yaml"), "input: outside\n")
+	writeFile(t, filepath.Join(root, "project", "go.mod"), "module example.com/project\n")
+	dir := filepath.Join(root, "project", "internal", "models")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Discover(dir); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("Discover() error = %v, want ErrConfigNotFound: gen.yaml above the project root must be ignored", err)
+	}
+
+	writeFile(t, filepath.Join(root, "project", "gen.yaml"), "input: migrations\n")
+
+	cfg, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Input != "migrations" {
+		t.Errorf("Input = %q, want the project config", cfg.Input)
+	}
+}
+
+func TestDiscoverOutsideProject(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "gen.yaml"), "input: outside\n")
+	dir := filepath.Join(root, "nested")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Discover(dir); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("Discover() error = %v, want ErrConfigNotFound outside a project", err)
+	}
+
+	cfg, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Input != "outside" {
+		t.Errorf("Input = %q, want the config of the directory itself", cfg.Input)
+	}
+}
+
+func TestLoadRejectsInvalidGoType(t *testing.T) {
+	tests := []string{
+		"types:\n  sql:\n    tinyint(1): 1+2\n",
+		"types:\n  columns:\n    users.id: f()\n",
+	}
+
+	for _, content := range tests {
+		path := filepath.Join(t.TempDir(), "gen.yaml")
+		writeFile(t, path, content)
+
+		if _, err := Load(path); !errors.Is(err, ErrInvalidGoType) {
+			t.Errorf("Load(%q) error = %v, want %v", content, err, ErrInvalidGoType)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+)
+
+var ErrInvalidGoType = errors.New("not a Go type")
+
+// ValidateGoType проверяет, что строка записывает Go тип: int64, *time.Time, sql.Null[string], map[string]any.
+// Так проверяются и типы из конфигурации, и типы, введённые в TUI.
+func ValidateGoType(goType string) error {
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidGoType, goType, err)
+	}
+
+	if !isTypeExpr(expr) {
+		return fmt.Errorf("%w: %q", ErrInvalidGoType, goType)
+	}
+
+	return nil
+}
+
+// isTypeExpr сообщает, что выражение записывает тип: ParseExpr принимает и 1+2 или f().
+func isTypeExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.FuncType, *ast.InterfaceType, *ast.StructType:
+		return true
+	case *ast.SelectorExpr:
+		_, ok := e.X.(*ast.Ident)
+
+		return ok
+	case *ast.ParenExpr:
+		return isTypeExpr(e.X)
+	case *ast.StarExpr:
+		return isTypeExpr(e.X)
+	case *ast.ArrayType:
+		return isArrayLen(e.Len) && isTypeExpr(e.Elt)
+	case *ast.MapType:
+		return isTypeExpr(e.Key) && isTypeExpr(e.Value)
+	case *ast.ChanType:
+		return isTypeExpr(e.Value)
+	case *ast.IndexExpr:
+		// Обобщённый тип: sql.Null[string].
+		return isTypeExpr(e.X) && isTypeExpr(e.Index)
+	case *ast.IndexListExpr:
+		return isTypeExpr(e.X) && !slices.ContainsFunc(e.Indices, func(index ast.Expr) bool { return !isTypeExpr(index) })
+	default:
+		return false
+	}
+}
+
+// isArrayLen длина массива: нет у среза, число или константа.
+func isArrayLen(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case nil, *ast.Ident:
+		return true
+	case *ast.BasicLit:
+		return e.Kind == token.INT
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrConfigExists = errors.New("config file already exists")
+
+const scaffold = `# Config for go-generator-repository. CLI flags override values from this file.
+# Paths are relative to this file.
+
+# Directory with migration files.
+input: ./migrations
+dialect: mysql
+
+output:
+  path: ./models
+  # Package of generated files, defaults to the last segment of output.path.
+  package: models
+  # import_path: github.com/acme/app/models
+  # flat (one package), schema (package per schema) or directory (package per migration subdirectory).
+  layout: flat
+  # Model file name template, "models.go" puts all models of a package in one file.
+  # file_name: "{{.Table.TableNames.Original}}_model.go"
+
+# Go type overrides by SQL type or by table.column pattern.
+types:
+  sql:
+    tinyint(1): bool
+  columns: {}
+
+tags:
+  db: true
+  # Name style for json tags: none, original, snake or camel.
+  json: none
+
+# Table patterns to generate, all tables when empty.
+tables: []
+
+# Patterns apply in order, "!name" brings back a name excluded by a previous pattern.
+exclude:
+  tables: []
+  # table.column patterns, for example "*.password".
+  columns: []
+
+naming:
+  initialisms: [ID, JSON, URL, UUID]
+  tables: {}
+  columns: {}
+
+# Generators to run: models and names of templates from the templates directory. All when empty.
+generators: []
+
+# Directory with custom templates: model.go.tmpl, table/<name>.go.tmpl, schema/<name>.go.tmpl.
+# templates: ./templates
+
+# External generators: get the parsed tables as JSON on stdin and print the files to write as JSON on stdout.
+# plugins:
+#   - name: typescript
+#     command: [./bin/gen-ts, --strict]
+#     parameter: "out=types"
+
+# Set to false to skip the interactive table selection.
+interactive: true
+
+log:
+  enabled: false
+  level: info
+`
+
+// Scaffold создаёт файл конфигурации с настройками по умолчанию и комментариями.
+func Scaffold(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%w: %s", ErrConfigExists, path)
+	}
+
+	return os.WriteFile(path, []byte(scaffold), 0o644)
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// Snapshot состояние таблиц до ручного выбора в TUI. Update сохраняет в конфигурацию только то,
+// что пользователь изменил относительно него.
+type Snapshot struct {
+	tables map[string]tableState
+}
+
+type tableState struct {
+	disabled bool
+	goName   string
+	columns  map[string]columnState
+}
+
+type columnState struct {
+	disabled bool
+	goName   string
+	goType   string
+}
+
+func TakeSnapshot(databases []*model.Database) *Snapshot {
+	snapshot := &Snapshot{tables: make(map[string]tableState, len(databases))}
+	for _, db := range databases {
+		state := tableState{
+			disabled: db.Disabled,
+			goName:   db.TableNames.CamelCase,
+			columns:  make(map[string]columnState, len(db.Columns)),
+		}
+
+		for _, column := range db.Columns {
+			state.columns[column.OriginalName] = columnState{
+				disabled: column.IsDisable,
+				goName:   column.CamelCaseName,
+				goType:   column.Type,
+			}
+		}
+
+		snapshot.tables[db.TableNames.Original] = state
+	}
+
+	return snapshot
+}
+
+// Update переносит изменения относительно snapshot в конфигурацию и сообщает, было ли что-то изменено.
+func (c *Config) Update(snapshot *Snapshot, databases []*model.Database) bool {
+	var changed bool
+	for _, db := range databases {
+		tableName := db.TableNames.Original
+		before, ok := snapshot.tables[tableName]
+		if !ok {
+			continue
+		}
+
+		if before.disabled != db.Disabled {
+			changed = true
+			c.setTableDisabled(tableName, db.Disabled)
+		}
+
+		if before.goName != db.TableNames.CamelCase {
+			changed = true
+			c.Naming.Tables = setValue(c.Naming.Tables, tableName, db.TableNames.CamelCase)
+		}
+
+		for _, column := range db.Columns {
+			columnKey := tableName + "." + column.OriginalName
+			columnBefore, ok := before.columns[column.OriginalName]
+			if !ok {
+				// Колонка появилась в TUI из строки, которую парсер не разобрал: сохраняем назначенный тип.
+				changed = true
+				c.Types.Columns = setValue(c.Types.Columns, columnKey, column.Type)
+				columnBefore = columnState{goName: column.CamelCaseName, goType: column.Type}
+			}
+
+			if columnBefore.disabled != column.IsDisable {
+				changed = true
+				c.Exclude.Columns = setListed(c.Exclude.Columns, columnKey, column.IsDisable)
+			}
+
+			if columnBefore.goName != column.CamelCaseName {
+				changed = true
+				c.Naming.Columns = setValue(c.Naming.Columns, columnKey, column.CamelCaseName)
+			}
+
+			if columnBefore.goType != column.Type {
+				changed = true
+				c.Types.Columns = setValue(c.Types.Columns, columnKey, column.Type)
+			}
+		}
+	}
+
+	return changed
+}
+
+func (c *Config) setTableDisabled(tableName string, disabled bool) {
+	c.Exclude.Tables = setListed(c.Exclude.Tables, tableName, disabled)
+	if disabled || len(c.Tables) == 0 || matchAny(c.Tables, tableName) {
+		return
+	}
+
+	// Таблица включена, но не попадает в список tables - добавляем её туда.
+	c.Tables = append(c.Tables, tableName)
+}
+
+// setListed добавляет имя в список исключений или убирает его оттуда. Если имя исключено шаблоном (audit_*),
+// для его возврата в список добавляется исключение из шаблона "!audit_log", см. IsExcluded.
+func setListed(list []string, name string, listed bool) []string {
+	list = slices.DeleteFunc(list, func(value string) bool {
+		return value == name || value == excludeNegation+name
+	})
+
+	if IsExcluded(list, name) == listed {
+		return list
+	}
+
+	if listed {
+		return append(list, name)
+	}
+
+	return append(list, excludeNegation+name)
+}
+
+// excludeNegation префикс шаблона исключений, который возвращает совпавшие с ним имена.
+const excludeNegation = "!"
+
+// IsExcluded сообщает, исключено ли имя списком шаблонов exclude. Как в .gitignore, шаблоны применяются по порядку,
+// а шаблон с "!" возвращает исключённое ранее имя: ["audit_*", "!audit_log"] исключает все audit_ таблицы,
+// кроме audit_log.
+func IsExcluded(patterns []string, name string) bool {
+	excluded := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, excludeNegation)
+		if matched, _ := path.Match(strings.TrimPrefix(pattern, excludeNegation), name); matched {
+			excluded = !negated
+		}
+	}
+
+	return excluded
+}
+
+func setValue(values map[string]string, key, value string) map[string]string {
+	if values == nil {
+		values = make(map[string]string)
+	}
+
+	values[key] = value
+
+	return values
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// persistedKeys секции, которые меняет Update. Остальное содержимое файла и комментарии сохраняются как есть.
+var persistedKeys = []string{"tables", "exclude", "naming", "types"}
+
+// Save записывает выбор таблиц, имена и типы в файл конфигурации, создавая его при необходимости.
+func (c *Config) Save(path string) error {
+	document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	if data, err := os.ReadFile(path); err == nil && len(bytes.TrimSpace(data)) > 0 {
+		if err = yaml.Unmarshal(data, document); err != nil {
+			return err
+		}
+	}
+
+	root := document.Content[0]
+	values := map[string]any{"tables": c.Tables, "exclude": c.Exclude, "naming": c.Naming, "types": c.Types}
+	for _, key := range persistedKeys {
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(values[key]); err != nil {
+			return err
+		}
+
+		// Пустые секции не пишутся, а ставшие пустыми удаляются из файла.
+		if (valueNode.Kind == yaml.MappingNode || valueNode.Kind == yaml.SequenceNode) && len(valueNode.Content) == 0 {
+			deleteMappingValue(root, key)
+
+			continue
+		}
+
+		setMappingValue(root, key, valueNode)
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	c.path = path
+
+	return nil
+}
+
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func deleteMappingValue(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func TestIsExcluded(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{patterns: nil, name: "users", want: false},
+		{patterns: []string{"audit_*"}, name: "audit_log", want: true},
+		{patterns: []string{"audit_*", "!audit_log"}, name: "audit_log", want: false},
+		{patterns: []string{"audit_*", "!audit_log"}, name: "audit_event", want: true},
+		{patterns: []string{"!audit_log", "audit_*"}, name: "audit_log", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := IsExcluded(tt.patterns, tt.name); got != tt.want {
+			t.Errorf("IsExcluded(%q, %q) = %v, want %v", tt.patterns, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateRestoresTableExcludedByPattern(t *testing.T) {
+	cfg := &Config{Exclude: Exclude{Tables: []string{"audit_*"}}}
+	databases := []*model.Database{{TableNames: model.TableNames{Original: "audit_log"}, Disabled: true}}
+
+	snapshot := TakeSnapshot(databases)
+	databases[0].Disabled = false
+	if !cfg.Update(snapshot, databases) {
+		t.Fatal("Update() = false, want true")
+	}
+
+	if want := []string{"audit_*", "!audit_log"}; !slices.Equal(cfg.Exclude.Tables, want) {
+		t.Fatalf("Exclude.Tables = %q, want %q", cfg.Exclude.Tables, want)
+	}
+
+	snapshot = TakeSnapshot(databases)
+	databases[0].Disabled = true
+	cfg.Update(snapshot, databases)
+
+	if want := []string{"audit_*"}; !slices.Equal(cfg.Exclude.Tables, want) {
+		t.Errorf("Exclude.Tables after disabling again = %q, want %q", cfg.Exclude.Tables, want)
+	}
+}
+
+func TestUpdateColumnChanges(t *testing.T) {
+	cfg := &Config{}
+	databases := []*model.Database{{
+		TableNames: model.TableNames{Original: "users", CamelCase: "Users"},
+		Columns:    []model.Column{{OriginalName: "password", CamelCaseName: "Password", Type: "string"}},
+	}}
+
+	snapshot := TakeSnapshot(databases)
+	if cfg.Update(snapshot, databases) {
+		t.Fatal("Update() without changes = true, want false")
+	}
+
+	databases[0].Columns[0].IsDisable = true
+	databases[0].Columns[0].Type = "[]byte"
+	databases[0].TableNames.CamelCase = "Account"
+	cfg.Update(snapshot, databases)
+
+	if want := []string{"users.password"}; !slices.Equal(cfg.Exclude.Columns, want) {
+		t.Errorf("Exclude.Columns = %q, want %q", cfg.Exclude.Columns, want)
+	}
+
+	if got := cfg.Types.Columns["users.password"]; got != "[]byte" {
+		t.Errorf("Types.Columns[users.password] = %q, want []byte", got)
+	}
+
+	if got := cfg.Naming.Tables["users"]; got != "Account" {
+		t.Errorf("Naming.Tables[users] = %q, want Account", got)
+	}
+}
+
+func TestSaveOmitsEmptySections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gen.yaml")
+	cfg := &Config{Exclude: Exclude{Tables: []string{"users"}}, Naming: Naming{Tables: map[string]string{}}}
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, path, "exclude:\n  tables:\n    - users\n")
+}
+
+func TestSaveKeepsOtherKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gen.yaml")
+	writeFile(t, path, "# models of the shop\ninput: migrations # relative to this file\ntables: [users]\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Tables = nil
+	cfg.Types.Columns = map[string]string{"users.id": "int64"}
+	if err = cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, path, "# models of the shop\ninput: migrations # relative to this file\ntypes:\n  columns:\n    users.id: int64\n")
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != want {
+		t.Errorf("%s:\n%s\nwant:\n%s", filepath.Base(path), data, want)
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/FireAnomaly/go-generator-repository/templater"
+)
+
+// FileDiff расхождение между сгенерированным файлом и файлом на диске.
+type FileDiff struct {
+	Name string
+	// Unified разница в формате diff -u, где старая версия - файл на диске.
+	Unified string
+}
+
+// Compare сравнивает файлы, собранные в output, с файлами в dir. Файл, которого нет на диске, тоже считается
+// расхождением. Результат отсортирован по имени файла.
+func Compare(output *templater.MemoryOutput, dir string) ([]FileDiff, error) {
+	var diffs []FileDiff
+	for _, name := range slices.Sorted(maps.Keys(output.Files)) {
+		generated := output.Files[name]
+		path := filepath.Join(dir, name)
+
+		onDisk, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		if bytes.Equal(onDisk, generated) {
+			continue
+		}
+
+		fromFile, before := path, difflib.SplitLines(string(onDisk))
+		if onDisk == nil {
+			fromFile, before = "/dev/null", nil
+		}
+
+		unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        before,
+			B:        difflib.SplitLines(string(generated)),
+			FromFile: fromFile,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		diffs = append(diffs, FileDiff{Name: name, Unified: unified})
+	}
+
+	return diffs, nil
+}
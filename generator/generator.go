@@ -0,0 +1,452 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/config"
+	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/parsers/mysql"
+	"github.com/FireAnomaly/go-generator-repository/plugin"
+	"github.com/FireAnomaly/go-generator-repository/templater"
+)
+
+var (
+	ErrNoInput            = errors.New("input is required: set Input or Reader")
+	ErrNoOutput           = errors.New("output is required: set Output or OutputDir")
+	ErrUnsupportedDialect = errors.New("unsupported dialect")
+)
+
+const (
+	DialectMySQL       = "mysql"
+	DefaultPackageName = "models"
+)
+
+// MigrationParser разбирает одну миграцию. name нужен только для сообщений и может быть пустым.
+type MigrationParser interface {
+	ParseMigration(name string, data []byte) (*model.Database, error)
+}
+
+// SchemaParser парсер, который применяет миграцию к схеме целиком: CREATE, ALTER и DROP TABLE по порядку.
+// Если Parser его реализует, миграции без CREATE TABLE не ломают разбор, а меняют уже разобранные таблицы.
+type SchemaParser interface {
+	// ApplyMigration возвращает новую схему и false, если в миграции нет выражений для таблиц.
+	ApplyMigration(databases []*model.Database, name string, data []byte) ([]*model.Database, bool, error)
+}
+
+// Options описывает один запуск генерации. Нулевые значения необязательных полей берутся по умолчанию.
+type Options struct {
+	// Input файлы миграций (*.sql в корне, для templater.LayoutDirectory и в поддиректориях), например os.DirFS
+	// или embed.FS.
+	Input fs.FS
+	// Reader одна миграция, например из stdin. Если задан, Input не используется.
+	Reader io.Reader
+	// Dialect SQL диалект миграций, пустая строка равна DialectMySQL.
+	Dialect string
+	// Parser заменяет встроенный парсер диалекта.
+	Parser MigrationParser
+	// Config правила именования и переопределения типов из gen.yaml.
+	Config    *config.Config
+	Templater templater.Options
+	// Select вызывается после разбора и до генерации: может отключить таблицы и колонки, переименовать их и т.д.
+	// Ошибка прерывает генерацию.
+	Select func(ctx context.Context, databases []*model.Database) error
+	// Output если задан, файлы пишутся в него (templater.WriterOutput, templater.NewZipOutput и т.д.),
+	// иначе в OutputDir.
+	Output    templater.Output
+	OutputDir string
+	// Prune удаляет из OutputDir файлы, которые генератор создал раньше, но больше не генерирует.
+	// Без него такие файлы только возвращаются в Result.Orphans.
+	Prune bool
+	// Plugins внешние генераторы. Запускаются, если Templater.Generators пуст или содержит имя плагина.
+	Plugins []plugin.Plugin
+	Logger  *zap.Logger
+}
+
+type Result struct {
+	// Files записанные файлы относительно корня вывода.
+	Files []string
+	// Orphans ранее сгенерированные файлы в OutputDir, которые больше не генерируются.
+	Orphans []string
+	// Pruned true, если Orphans удалены.
+	Pruned bool
+	// Warnings предупреждения генерации, например об областях gen:keep со ссылками на удалённые поля.
+	Warnings []string
+}
+
+// Generate разбирает миграции и генерирует модели включенных таблиц.
+func Generate(ctx context.Context, opts Options) (*Result, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	logger = logger.Named("Generator: ")
+
+	if opts.Output == nil && opts.OutputDir == "" {
+		return nil, ErrNoOutput
+	}
+
+	databases, warnings, err := ParseWithWarnings(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Config != nil {
+		opts.Config.Apply(databases)
+	}
+
+	if opts.Select != nil {
+		if err = opts.Select(ctx, databases); err != nil {
+			logger.Debug("Select error", zap.Error(err))
+
+			return nil, err
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	isDirOutput := opts.Output == nil
+	output := &recordingOutput{Output: opts.Output}
+	if isDirOutput {
+		output.Output = templater.DirOutput{Dir: opts.OutputDir}
+	}
+
+	if opts.Templater.Version == "" {
+		opts.Templater.Version = Version()
+	}
+
+	// Без директории имя пакета по умолчанию взять неоткуда.
+	if opts.OutputDir == "" && opts.Templater.PackageName == "" {
+		opts.Templater.PackageName = DefaultPackageName
+	}
+
+	// Плагины запускаются до записи, чтобы при их ошибке в output ничего не попало.
+	pluginFiles, err := runPlugins(ctx, opts, databases)
+	if err != nil {
+		return nil, err
+	}
+
+	// Модели копятся в памяти, чтобы файлы плагинов можно было проверить на конфликты до записи.
+	models := &bufferedOutput{}
+	templaterOptions, runTemplater := withoutPlugins(opts.Templater, opts.Plugins)
+	if runTemplater {
+		modelTemplater := templater.NewTemplater(opts.Logger, templaterOptions)
+		if err = modelTemplater.WriteModels(databases, models, opts.OutputDir); err != nil {
+			return nil, err
+		}
+
+		warnings = append(warnings, modelTemplater.Warnings()...)
+	}
+
+	if err = checkPluginFiles(pluginFiles, models.names()); err != nil {
+		return nil, err
+	}
+
+	for _, file := range models.files {
+		if err = output.WriteFile(file.name, file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, file := range pluginFiles {
+		if err = output.WriteFile(file.Name, []byte(file.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{Files: output.names, Warnings: warnings}
+	if opts.OutputDir == "" {
+		return result, nil
+	}
+
+	manifest, err := LoadManifest(opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Orphans = manifest.Orphans(result.Files)
+	if !isDirOutput {
+		// Файлы записаны не в OutputDir (например, в -check), поэтому манифест и директория не меняются.
+		return result, nil
+	}
+
+	if opts.Prune && len(result.Orphans) > 0 {
+		if err = prune(opts.OutputDir, result.Orphans); err != nil {
+			return nil, fmt.Errorf("failed to remove stale files: %w", err)
+		}
+
+		logger.Info("Stale files removed", zap.Strings("files", result.Orphans))
+		result.Pruned = true
+	}
+
+	// Неудалённые файлы остаются в манифесте, чтобы о них сообщалось и в следующий раз.
+	manifest.Files = slices.Clone(result.Files)
+	if !result.Pruned {
+		manifest.Files = append(manifest.Files, result.Orphans...)
+	}
+
+	if err = manifest.Save(opts.OutputDir); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", ManifestName, err)
+	}
+
+	return result, nil
+}
+
+// pluginFile файл, который вернул плагин generator.
+type pluginFile struct {
+	plugin.File
+	generator string
+}
+
+func runPlugins(ctx context.Context, opts Options, databases []*model.Database) ([]pluginFile, error) {
+	if len(opts.Plugins) == 0 {
+		return nil, nil
+	}
+
+	request := plugin.Request{PackageName: opts.Templater.PackageName, ImportPath: opts.Templater.ImportPath}
+	if request.PackageName == "" {
+		request.PackageName = filepath.Base(opts.OutputDir)
+	}
+
+	for _, db := range databases {
+		if db != nil && !db.Disabled {
+			request.Tables = append(request.Tables, db)
+		}
+	}
+
+	var files []pluginFile
+	for _, p := range opts.Plugins {
+		if len(opts.Templater.Generators) > 0 && !slices.Contains(opts.Templater.Generators, p.Name) {
+			continue
+		}
+
+		pluginFiles, err := plugin.Run(ctx, p, request)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range pluginFiles {
+			files = append(files, pluginFile{File: file, generator: p.Name})
+		}
+	}
+
+	return files, nil
+}
+
+// checkPluginFiles не даёт плагинам перезаписать модели, файлы других плагинов, манифест и файлы с ручным кодом.
+func checkPluginFiles(files []pluginFile, models []string) error {
+	generators := make(map[string]string, len(models)+len(files))
+	for _, name := range models {
+		generators[name] = templater.GeneratorModels
+	}
+
+	for _, file := range files {
+		name := path.Clean(filepath.ToSlash(file.Name))
+		switch {
+		case name == ManifestName:
+			return fmt.Errorf("%w: %s of plugin %s is the generator manifest", templater.ErrFileNameConflict,
+				file.Name, file.generator)
+		case strings.HasSuffix(name, templater.ExtFileSuffix):
+			return fmt.Errorf("%w: %s of plugin %s is reserved for hand-written code", templater.ErrFileNameConflict,
+				file.Name, file.generator)
+		case generators[name] != "":
+			return fmt.Errorf("%w: %s is generated by %s and plugin %s", templater.ErrFileNameConflict, file.Name,
+				generators[name], file.generator)
+		}
+
+		generators[name] = "plugin " + file.generator
+	}
+
+	return nil
+}
+
+// withoutPlugins убирает имена плагинов из списка генераторов шаблонизатора. false означает, что в списке
+// были только плагины и шаблонизатор запускать не нужно.
+func withoutPlugins(options templater.Options, plugins []plugin.Plugin) (templater.Options, bool) {
+	if len(options.Generators) == 0 {
+		return options, true
+	}
+
+	options.Generators = slices.DeleteFunc(slices.Clone(options.Generators), func(generator string) bool {
+		return slices.ContainsFunc(plugins, func(p plugin.Plugin) bool { return p.Name == generator })
+	})
+
+	return options, len(options.Generators) > 0
+}
+
+// Parse разбирает миграции из Input или Reader без генерации кода. Миграции отката (*.down.sql) пропускаются.
+func Parse(ctx context.Context, opts Options) ([]*model.Database, error) {
+	databases, _, err := ParseWithWarnings(ctx, opts)
+
+	return databases, err
+}
+
+// ParseWithWarnings как Parse, но также возвращает предупреждения о пропущенных миграциях.
+func ParseWithWarnings(ctx context.Context, opts Options) ([]*model.Database, []string, error) {
+	parser, err := newParser(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Reader != nil {
+		data, err := io.ReadAll(opts.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read migration: %w", err)
+		}
+
+		databases, _, err := applyMigration(parser, nil, "", data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(databases) == 0 {
+			return nil, nil, model.ErrMigrationNotFound
+		}
+
+		return databases, nil, nil
+	}
+
+	if opts.Input == nil {
+		return nil, nil, ErrNoInput
+	}
+
+	names, err := migrationNames(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding migrations: %w", err)
+	}
+
+	mysql.SortMigrations(names)
+
+	var (
+		databases []*model.Database
+		warnings  []string
+	)
+	for _, name := range names {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		data, err := fs.ReadFile(opts.Input, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var applied bool
+		databases, applied, err = applyMigration(parser, databases, path.Clean(name), data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if !applied {
+			warnings = append(warnings, fmt.Sprintf("%s: no CREATE, ALTER or DROP TABLE statements, skipped", name))
+		}
+	}
+
+	if len(databases) == 0 {
+		return nil, nil, model.ErrMigrationNotFound
+	}
+
+	return databases, warnings, nil
+}
+
+// applyMigration применяет миграцию через SchemaParser или, если парсер его не реализует, разбирает её как одну
+// таблицу.
+func applyMigration(parser MigrationParser, databases []*model.Database, name string, data []byte) (
+	[]*model.Database, bool, error,
+) {
+	if schemaParser, ok := parser.(SchemaParser); ok {
+		return schemaParser.ApplyMigration(databases, name, data)
+	}
+
+	database, err := parser.ParseMigration(name, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return append(databases, database), true, nil
+}
+
+// modulePath путь модуля генератора, по нему версия ищется среди зависимостей, если генератор используется
+// как библиотека.
+const modulePath = "github.com/FireAnomaly/go-generator-repository"
+
+// rePseudoVersion псевдоверсия Go модуля без тега: v0.0.0-20261019020811-b1f85e0982bf, v1.2.4-0.20261019020811-...
+var rePseudoVersion = regexp.MustCompile(`(^|[-.])\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+// Version версия генератора из информации о сборке, если он собран из тега модуля. Для сборки из исходников,
+// псевдоверсии или дерева с изменениями возвращается пустая строка: такая версия меняется с каждым коммитом,
+// и заголовки всех файлов переписывались бы при каждой пересборке, а -check находил бы расхождения.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	version := info.Main.Version
+	if info.Main.Path != modulePath {
+		version = ""
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+			}
+		}
+	}
+
+	if !strings.HasPrefix(version, "v") || strings.Contains(version, "+dirty") || rePseudoVersion.MatchString(version) {
+		return ""
+	}
+
+	return version
+}
+
+// migrationNames *.sql в корне Input, а для templater.LayoutDirectory и во всех поддиректориях, кроме миграций
+// отката.
+func migrationNames(opts Options) ([]string, error) {
+	if opts.Templater.Layout != templater.LayoutDirectory {
+		names, err := fs.Glob(opts.Input, "*.sql")
+
+		return slices.DeleteFunc(names, mysql.IsDownMigration), err
+	}
+
+	var names []string
+	err := fs.WalkDir(opts.Input, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() && path.Ext(name) == ".sql" && !mysql.IsDownMigration(name) {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+
+	return names, err
+}
+
+func newParser(opts Options) (MigrationParser, error) {
+	if opts.Parser != nil {
+		return opts.Parser, nil
+	}
+
+	switch opts.Dialect {
+	case "", DialectMySQL:
+		return mysql.NewParser(opts.Logger), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, opts.Dialect)
+	}
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/FireAnomaly/go-generator-repository/plugin"
+	"github.com/FireAnomaly/go-generator-repository/templater"
+)
+
+func TestGenerateHeaderListsAlterMigrations(t *testing.T) {
+	input := fstest.MapFS{
+		"001_users.up.sql":        {Data: []byte("CREATE TABLE users\n(\n    id INT PRIMARY KEY\n);\n")},
+		"002_users_name.up.sql":   {Data: []byte("-- name of the user\nALTER TABLE users\n    ADD COLUMN name TEXT NOT NULL;\n")},
+		"002_users_name.down.sql": {Data: []byte("ALTER TABLE users DROP COLUMN name;\n")},
+	}
+
+	output := templater.NewMemoryOutput()
+	_, err := Generate(context.Background(), Options{
+		Input:     input,
+		Output:    output,
+		Templater: templater.Options{PackageName: "models", Version: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	code := string(output.Files["users_model.go"])
+	wantHeader := templater.HeaderLine + "\n// version: v1.0.0\n// source: 001_users.up.sql\n// source: 002_users_name.up.sql\n\n"
+	if !strings.HasPrefix(code, wantHeader) {
+		t.Errorf("users_model.go header =\n%s\nwant\n%s", code[:min(len(code), len(wantHeader))], wantHeader)
+	}
+
+	if !strings.Contains(code, `db:"name"`) {
+		t.Errorf("users_model.go has no name field:\n%s", code)
+	}
+}
+
+func TestParseRecordsColumnSources(t *testing.T) {
+	databases, err := Parse(context.Background(), Options{Input: fstest.MapFS{
+		"001_users.sql": {Data: []byte("CREATE TABLE users\n(\n    id INT PRIMARY KEY\n);\n")},
+		"002_users.sql": {Data: []byte("-- add name\nALTER TABLE users\n    ADD COLUMN name TEXT,\n    ADD COLUMN age INT;\n")},
+	}})
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	want := map[string]struct {
+		file string
+		line int
+	}{
+		"id":   {file: "001_users.sql", line: 3},
+		"name": {file: "002_users.sql", line: 3},
+		"age":  {file: "002_users.sql", line: 4},
+	}
+
+	for _, column := range databases[0].Columns {
+		if got := want[column.OriginalName]; column.SourceFile != got.file || column.LineNumber != got.line {
+			t.Errorf("column %s source = %s:%d, want %s:%d",
+				column.OriginalName, column.SourceFile, column.LineNumber, got.file, got.line)
+		}
+	}
+}
+
+func TestCheckPluginFiles(t *testing.T) {
+	models := []string{"users_model.go", "shop/orders_model.go"}
+	tests := []struct {
+		name    string
+		files   []pluginFile
+		wantErr bool
+	}{
+		{name: "own files", files: []pluginFile{{File: plugin.File{Name: "types/users.ts"}, generator: "ts"}}},
+		{name: "model file", files: []pluginFile{{File: plugin.File{Name: "users_model.go"}, generator: "ts"}}, wantErr: true},
+		{name: "model file in package", files: []pluginFile{{File: plugin.File{Name: "shop/./orders_model.go"}, generator: "ts"}}, wantErr: true},
+		{name: "manifest", files: []pluginFile{{File: plugin.File{Name: ManifestName}, generator: "ts"}}, wantErr: true},
+		{name: "ext file", files: []pluginFile{{File: plugin.File{Name: "users" + templater.ExtFileSuffix}, generator: "ts"}}, wantErr: true},
+		{
+			name: "two plugins",
+			files: []pluginFile{
+				{File: plugin.File{Name: "types/users.ts"}, generator: "ts"},
+				{File: plugin.File{Name: "types/users.ts"}, generator: "flow"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		err := checkPluginFiles(tt.files, models)
+		if tt.wantErr && !errors.Is(err, templater.ErrFileNameConflict) || !tt.wantErr && err != nil {
+			t.Errorf("%s: checkPluginFiles() error = %v, want conflict %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/FireAnomaly/go-generator-repository/templater"
+)
+
+// ManifestName файл в директории вывода со списком файлов, которые создал генератор. Только эти файлы
+// генератор считает своими и может удалить.
+const ManifestName = ".gen-manifest.json"
+
+type Manifest struct {
+	Files []string `json:"files"`
+}
+
+// LoadManifest читает манифест из dir. Если его нет, возвращается пустой манифест.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestName, err)
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) Save(dir string) error {
+	slices.Sort(m.Files)
+	m.Files = slices.Compact(m.Files)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return templater.DirOutput{Dir: dir}.WriteFile(ManifestName, append(data, '\n'))
+}
+
+// Orphans файлы из манифеста, которые больше не генерируются: таблица удалена из миграций или отключена.
+func (m *Manifest) Orphans(generated []string) []string {
+	var orphans []string
+	for _, name := range m.Files {
+		if !slices.Contains(generated, name) && filepath.IsLocal(name) {
+			orphans = append(orphans, name)
+		}
+	}
+
+	return orphans
+}
+
+// prune удаляет файлы из dir. Уже удалённые вручную файлы пропускаются.
+func prune(dir string, names []string) error {
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bufferedOutput копит файлы в памяти в порядке записи.
+type bufferedOutput struct {
+	files []bufferedFile
+}
+
+type bufferedFile struct {
+	name string
+	data []byte
+}
+
+func (o *bufferedOutput) WriteFile(name string, data []byte) error {
+	o.files = append(o.files, bufferedFile{name: name, data: data})
+
+	return nil
+}
+
+func (o *bufferedOutput) names() []string {
+	names := make([]string, 0, len(o.files))
+	for _, file := range o.files {
+		names = append(names, path.Clean(filepath.ToSlash(file.name)))
+	}
+
+	return names
+}
+
+// recordingOutput запоминает имена записанных файлов.
+type recordingOutput struct {
+	templater.Output
+	names []string
+}
+
+func (o *recordingOutput) WriteFile(name string, data []byte) error {
+	if err := o.Output.WriteFile(name, data); err != nil {
+		return err
+	}
+
+	o.names = append(o.names, name)
+
+	return nil
+}
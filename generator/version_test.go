@@ -0,0 +1,20 @@
+package generator
+
+import "testing"
+
+func TestPseudoVersion(t *testing.T) {
+	tests := map[string]bool{
+		"v1.4.0":                                          false,
+		"v2.0.0-rc.1":                                     false,
+		"v0.0.0-20261019020811-b1f85e0982bf":              true,
+		"v1.2.4-0.20261019020811-b1f85e0982bf":            true,
+		"v1.2.4-rc.1.0.20261019020811-b1f85e0982bf":       true,
+		"v2.0.0-20261019020811-b1f85e0982bf+incompatible": true,
+	}
+
+	for version, want := range tests {
+		if got := rePseudoVersion.MatchString(version); got != want {
+			t.Errorf("rePseudoVersion.MatchString(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
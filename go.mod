@@ -8,7 +8,10 @@ require (
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/jedib0t/go-pretty/v6 v6.6.8
 	github.com/olekukonko/tablewriter v1.1.0
+	github.com/pmezard/go-difflib v1.0.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/term v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -25,6 +28,5 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
-	golang.org/x/term v0.36.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
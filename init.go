@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/FireAnomaly/go-generator-repository/config"
+)
+
+// runInit создаёт gen.yaml с настройками по умолчанию.
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	path := flags.String("path", config.FileNames[0], "Path of the config file to create")
+	force := flags.Bool("force", false, "Overwrite an existing config file")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.Scaffold(*path, *force); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s\n", *path)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FireAnomaly/go-generator-repository/linter"
+)
+
+// runLint проверяет схему из миграций и завершается с ошибкой, если есть замечания уровня -fail-on и выше.
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	migrationPathInput := flags.String("in", "", "Path to the migration files, - to read one migration from stdin (example: ./examples)")
+	format := flags.String("format", linter.FormatText, "Output format: text, json or sarif")
+	outputPath := flags.String("out", "", "Write the report to a file instead of stdout")
+	enable := flags.String("enable", "", "Comma separated rules to run, all rules when empty")
+	disable := flags.String("disable", "", "Comma separated rules to skip")
+	severities := flags.String("severity", "", "Comma separated severity overrides (example: missing-timestamps=warning, none disables the rule)")
+	failOnInput := flags.String("fail-on", "error", "Minimal severity that makes lint exit non-zero: info, warning, error or none")
+	listRules := flags.Bool("list", false, "List available rules and exit")
+	isLogOutput := flags.Bool("log", false, "Enable detailed logging")
+	logLevel := zapcore.InfoLevel
+	flags.Var(&logLevel, "loglevel", "Set the logging level")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *listRules {
+		for _, rule := range linter.Rules {
+			fmt.Printf("%-24s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+		}
+
+		return nil
+	}
+
+	logger := zap.NewNop()
+	if *isLogOutput {
+		var err error
+		logger, err = NewLogger(logLevel)
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+	}
+
+	if *migrationPathInput == "" {
+		return fmt.Errorf("migration path is required")
+	}
+
+	failOn, err := linter.ParseSeverity(*failOnInput)
+	if err != nil {
+		return err
+	}
+
+	config := linter.Config{
+		Enabled:    splitList(*enable),
+		Disabled:   splitList(*disable),
+		Severities: make(map[string]linter.Severity),
+	}
+	for _, override := range splitList(*severities) {
+		ruleID, severityName, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("invalid severity override %q, expected rule=severity", override)
+		}
+
+		config.Severities[ruleID], err = linter.ParseSeverity(severityName)
+		if err != nil {
+			return err
+		}
+	}
+
+	schemaLinter, err := linter.NewLinter(logger, config)
+	if err != nil {
+		return err
+	}
+
+	databases, _, err := parseMigrations(*migrationPathInput, logger)
+	if err != nil {
+		return fmt.Errorf("failed to get migrations: %w", err)
+	}
+
+	output := os.Stdout
+	if *outputPath != "" {
+		output, err = os.Create(*outputPath)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+
+	issues := schemaLinter.Lint(databases)
+	if err = linter.WriteReport(output, *format, issues, schemaLinter.EnabledRules()); err != nil {
+		return err
+	}
+
+	if linter.HasFailures(issues, failOn) {
+		return fmt.Errorf("lint failed: issues with severity %s or higher found", failOn)
+	}
+
+	return nil
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
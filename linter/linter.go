@@ -0,0 +1,165 @@
+package linter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+var (
+	ErrUnknownRule     = errors.New("unknown lint rule")
+	ErrUnknownSeverity = errors.New("unknown severity")
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+	// SeverityNone выше любого замечания, используется в FailOn, чтобы lint никогда не падал.
+	// В Config.Severities отключает правило.
+	SeverityNone
+)
+
+var severityNames = map[Severity]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+	SeverityNone:    "none",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return severity, nil
+		}
+	}
+
+	return SeverityNone, fmt.Errorf("%w: %s", ErrUnknownSeverity, name)
+}
+
+type Issue struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Table    string   `json:"table"`
+	Column   string   `json:"column,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Config выбирает правила и их уровни. Если Enabled не пуст, работают только перечисленные правила.
+// Уровень SeverityNone в Severities отключает правило так же, как Disabled.
+type Config struct {
+	Enabled    []string
+	Disabled   []string
+	Severities map[string]Severity
+}
+
+type Linter struct {
+	rules  []Rule
+	logger *zap.Logger
+}
+
+func NewLinter(logger *zap.Logger, config Config) (*Linter, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	known := make(map[string]bool, len(Rules))
+	for _, rule := range Rules {
+		known[rule.ID] = true
+	}
+
+	for _, ids := range [][]string{config.Enabled, config.Disabled} {
+		for _, id := range ids {
+			if !known[id] {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownRule, id)
+			}
+		}
+	}
+
+	for id := range config.Severities {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRule, id)
+		}
+	}
+
+	rules := make([]Rule, 0, len(Rules))
+	for _, rule := range Rules {
+		if len(config.Enabled) > 0 && !slices.Contains(config.Enabled, rule.ID) {
+			continue
+		}
+
+		if slices.Contains(config.Disabled, rule.ID) {
+			continue
+		}
+
+		if severity, ok := config.Severities[rule.ID]; ok {
+			if severity == SeverityNone {
+				continue
+			}
+
+			rule.Severity = severity
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return &Linter{rules: rules, logger: logger.Named("Linter: ")}, nil
+}
+
+// EnabledRules правила с учётом Config, нужны для описания правил в SARIF.
+func (l *Linter) EnabledRules() []Rule {
+	return l.rules
+}
+
+func (l *Linter) Lint(databases []*model.Database) []Issue {
+	var issues []Issue
+	for _, db := range databases {
+		if db == nil || db.Disabled {
+			continue
+		}
+
+		for _, rule := range l.rules {
+			for _, issue := range rule.Check(db) {
+				issue.RuleID = rule.ID
+				issue.Severity = rule.Severity
+				issue.Table = db.TableNames.Original
+				if issue.File == "" && len(db.SourceFiles) > 0 {
+					issue.File = db.SourceFiles[0]
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	l.logger.Debug("Lint finished", zap.Int("issuesCount", len(issues)))
+
+	return issues
+}
+
+// HasFailures сообщает, есть ли замечания с уровнем не ниже failOn. Замечания уровня SeverityNone не считаются.
+func HasFailures(issues []Issue, failOn Severity) bool {
+	for _, issue := range issues {
+		if issue.Severity != SeverityNone && issue.Severity >= failOn {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package linter
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func TestSeverityNoneDisablesRule(t *testing.T) {
+	linter, err := NewLinter(nil, Config{Severities: map[string]Severity{"missing-primary-key": SeverityNone}})
+	if err != nil {
+		t.Fatalf("NewLinter() error: %v", err)
+	}
+
+	issues := linter.Lint([]*model.Database{{TableNames: model.TableNames{Original: "logs"}}})
+	for _, issue := range issues {
+		if issue.RuleID == "missing-primary-key" {
+			t.Errorf("Lint() reported %+v for a rule with severity none", issue)
+		}
+	}
+
+	if HasFailures(issues, SeverityError) {
+		t.Errorf("HasFailures(%+v, error) = true, want false", issues)
+	}
+}
+
+func TestHasFailures(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		failOn   Severity
+		want     bool
+	}{
+		{severity: SeverityWarning, failOn: SeverityError, want: false},
+		{severity: SeverityError, failOn: SeverityError, want: true},
+		{severity: SeverityInfo, failOn: SeverityInfo, want: true},
+		{severity: SeverityError, failOn: SeverityNone, want: false},
+		{severity: SeverityNone, failOn: SeverityInfo, want: false},
+		{severity: SeverityNone, failOn: SeverityNone, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := HasFailures([]Issue{{Severity: tt.severity}}, tt.failOn); got != tt.want {
+			t.Errorf("HasFailures(%s issue, fail-on %s) = %v, want %v", tt.severity, tt.failOn, got, tt.want)
+		}
+	}
+}
+
+func TestNewLinterConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantIDs  []string
+		wantErr  error
+		severity map[string]Severity
+	}{
+		{
+			name:    "enabled",
+			config:  Config{Enabled: []string{"missing-timestamps", "missing-primary-key"}},
+			wantIDs: []string{"missing-primary-key", "missing-timestamps"},
+		},
+		{
+			name:    "disabled wins over enabled",
+			config:  Config{Enabled: []string{"missing-timestamps", "missing-primary-key"}, Disabled: []string{"missing-timestamps"}},
+			wantIDs: []string{"missing-primary-key"},
+		},
+		{
+			name:     "severity override",
+			config:   Config{Enabled: []string{"missing-timestamps"}, Severities: map[string]Severity{"missing-timestamps": SeverityError}},
+			wantIDs:  []string{"missing-timestamps"},
+			severity: map[string]Severity{"missing-timestamps": SeverityError},
+		},
+		{name: "unknown enabled", config: Config{Enabled: []string{"no-such-rule"}}, wantErr: ErrUnknownRule},
+		{name: "unknown disabled", config: Config{Disabled: []string{"no-such-rule"}}, wantErr: ErrUnknownRule},
+		{name: "unknown override", config: Config{Severities: map[string]Severity{"no-such-rule": SeverityInfo}}, wantErr: ErrUnknownRule},
+	}
+
+	for _, tt := range tests {
+		linter, err := NewLinter(nil, tt.config)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: NewLinter() error = %v, want %v", tt.name, err, tt.wantErr)
+
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		var ids []string
+		for _, rule := range linter.EnabledRules() {
+			ids = append(ids, rule.ID)
+			if severity, ok := tt.severity[rule.ID]; ok && rule.Severity != severity {
+				t.Errorf("%s: rule %s severity = %s, want %s", tt.name, rule.ID, rule.Severity, severity)
+			}
+		}
+
+		if !slices.Equal(ids, tt.wantIDs) {
+			t.Errorf("%s: enabled rules = %v, want %v", tt.name, ids, tt.wantIDs)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, name := range []string{"info", "WARNING", "error", "none"} {
+		severity, err := ParseSeverity(name)
+		if err != nil || !strings.EqualFold(severity.String(), name) {
+			t.Errorf("ParseSeverity(%q) = %s, %v", name, severity, err)
+		}
+	}
+
+	if _, err := ParseSeverity("fatal"); !errors.Is(err, ErrUnknownSeverity) {
+		t.Errorf("ParseSeverity(%q) error = %v, want %v", "fatal", err, ErrUnknownSeverity)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestWriteReport(t *testing.T) {
+	linter, err := NewLinter(nil, Config{Enabled: []string{"missing-primary-key", "nullable-boolean", "missing-timestamps"}})
+	if err != nil {
+		t.Fatalf("NewLinter() error: %v", err)
+	}
+
+	issues := linter.Lint([]*model.Database{{
+		TableNames: model.TableNames{Original: "users"},
+		Columns: []model.Column{{
+			OriginalName: "active", Type: "bool", IsNull: true, SourceFile: "migrations/002_users_active.sql", LineNumber: 2,
+		}},
+		SourceFiles: []string{"migrations/001_users.sql", "migrations/002_users_active.sql"},
+	}})
+
+	for _, format := range []string{FormatText, FormatJSON, FormatSARIF} {
+		var output bytes.Buffer
+		if err = WriteReport(&output, format, issues, linter.EnabledRules()); err != nil {
+			t.Fatalf("WriteReport(%s) error: %v", format, err)
+		}
+
+		assertGolden(t, "report."+format, output.Bytes())
+	}
+
+	if err = WriteReport(&bytes.Buffer{}, "xml", issues, nil); err == nil {
+		t.Error("WriteReport(xml) error = nil, want unknown format")
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test ./linter -update to rewrite it", path)
+	}
+}
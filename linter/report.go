@@ -0,0 +1,174 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// WriteReport выводит замечания в формате text, json или sarif.
+func WriteReport(w io.Writer, format string, issues []Issue, rules []Rule) error {
+	switch format {
+	case FormatText:
+		return writeText(w, issues)
+	case FormatJSON:
+		return writeJSON(w, issues)
+	case FormatSARIF:
+		return writeSARIF(w, issues, rules)
+	default:
+		return fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+func writeText(w io.Writer, issues []Issue) error {
+	for _, issue := range issues {
+		location := issue.Table
+		if issue.File != "" {
+			location = issue.File
+			if issue.Line > 0 {
+				location += fmt.Sprintf(":%d", issue.Line)
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, issue.Severity, issue.Message, issue.RuleID); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d issue(s) found\n", len(issues))
+
+	return err
+}
+
+func writeJSON(w io.Writer, issues []Issue) error {
+	if issues == nil {
+		issues = []Issue{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(issues)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevel SARIF называет info уровнем note.
+func sarifLevel(severity Severity) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+
+	return severity.String()
+}
+
+func writeSARIF(w io.Writer, issues []Issue, rules []Rule) error {
+	driver := sarifDriver{
+		Name:           "go-generator-repository",
+		InformationURI: "https://github.com/FireAnomaly/go-generator-repository",
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(issues))
+	for _, issue := range issues {
+		result := sarifResult{
+			RuleID:  issue.RuleID,
+			Level:   sarifLevel(issue.Severity),
+			Message: sarifMessage{Text: issue.Message},
+		}
+
+		if issue.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				// SARIF ждёт URI с прямыми слешами, а не путь ОС.
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(issue.File)},
+			}}
+			if issue.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line}
+			}
+
+			result.Locations = append(result.Locations, location)
+		}
+
+		results = append(results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
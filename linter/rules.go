@@ -0,0 +1,194 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/naming"
+)
+
+// Rule проверка схемы. Check возвращает замечания без RuleID и Severity, их проставляет Linter.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Check       func(db *model.Database) []Issue
+}
+
+var (
+	reSnakeCase   = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	reMoneyColumn = regexp.MustCompile(`(?i)(price|amount|cost|money|balance|total|sum|fee|salary|payment)`)
+	reFloatType   = regexp.MustCompile(`(?i)^(float|double|real)\b`)
+	reBoolTinyInt = regexp.MustCompile(`(?i)^tinyint\s*\(\s*1\s*\)`)
+)
+
+// Rules все встроенные правила в порядке вывода.
+var Rules = []Rule{
+	{
+		ID:          "missing-primary-key",
+		Description: "Table has no primary key",
+		Severity:    SeverityError,
+		Check:       checkMissingPrimaryKey,
+	},
+	{
+		ID:          "nullable-boolean",
+		Description: "Boolean column is nullable, which makes it three-state",
+		Severity:    SeverityWarning,
+		Check:       checkNullableBoolean,
+	},
+	{
+		ID:          "float-money",
+		Description: "FLOAT/DOUBLE used for a money column, use DECIMAL",
+		Severity:    SeverityWarning,
+		Check:       checkFloatMoney,
+	},
+	{
+		ID:          "enum-without-default",
+		Description: "ENUM column has no DEFAULT value",
+		Severity:    SeverityWarning,
+		Check:       checkEnumWithoutDefault,
+	},
+	{
+		ID:          "inconsistent-naming",
+		Description: "Table or column name is not snake_case",
+		Severity:    SeverityWarning,
+		Check:       checkNaming,
+	},
+	{
+		ID:          "missing-timestamps",
+		Description: "Table has no created_at/updated_at columns",
+		Severity:    SeverityInfo,
+		Check:       checkMissingTimestamps,
+	},
+	{
+		ID:          "unindexed-foreign-key",
+		Description: "Foreign key column is not the first column of any index",
+		Severity:    SeverityWarning,
+		Check:       checkUnindexedForeignKey,
+	},
+}
+
+func checkMissingPrimaryKey(db *model.Database) []Issue {
+	if len(db.PrimaryKey()) > 0 {
+		return nil
+	}
+
+	return []Issue{{Message: fmt.Sprintf("table %s has no primary key", db.TableNames.Original)}}
+}
+
+func checkNullableBoolean(db *model.Database) []Issue {
+	var issues []Issue
+	for _, column := range db.Columns {
+		isBool := column.Type == "bool" || reBoolTinyInt.MatchString(column.SQLType)
+		if isBool && column.IsNull {
+			issues = append(issues, columnIssue(column, "boolean column %s is nullable, add NOT NULL", column.OriginalName))
+		}
+	}
+
+	return issues
+}
+
+func checkFloatMoney(db *model.Database) []Issue {
+	var issues []Issue
+	for _, column := range db.Columns {
+		isFloat := reFloatType.MatchString(column.SQLType) ||
+			(column.SQLType == "" && strings.HasPrefix(column.Type, "float"))
+		if isFloat && reMoneyColumn.MatchString(column.OriginalName) {
+			issues = append(issues, columnIssue(column, "money column %s uses %s, use DECIMAL",
+				column.OriginalName, column.SQLType))
+		}
+	}
+
+	return issues
+}
+
+func checkEnumWithoutDefault(db *model.Database) []Issue {
+	var issues []Issue
+	for _, column := range db.Columns {
+		if column.IsEnum() && column.DefaultValue == nil {
+			issues = append(issues, columnIssue(column, "enum column %s has no default value", column.OriginalName))
+		}
+	}
+
+	return issues
+}
+
+func checkNaming(db *model.Database) []Issue {
+	var issues []Issue
+	if !reSnakeCase.MatchString(db.TableNames.Original) {
+		issues = append(issues, Issue{Message: fmt.Sprintf("table name %s is not snake_case (suggest %s)",
+			db.TableNames.Original, naming.ToSnakeCase(db.TableNames.Original))})
+	}
+
+	for _, column := range db.Columns {
+		if !reSnakeCase.MatchString(column.OriginalName) {
+			issues = append(issues, columnIssue(column, "column name %s is not snake_case (suggest %s)",
+				column.OriginalName, naming.ToSnakeCase(column.OriginalName)))
+		}
+	}
+
+	return issues
+}
+
+func checkMissingTimestamps(db *model.Database) []Issue {
+	var hasCreated, hasUpdated bool
+	for _, column := range db.Columns {
+		switch naming.ToSnakeCase(column.OriginalName) {
+		case "created_at":
+			hasCreated = true
+		case "updated_at":
+			hasUpdated = true
+		}
+	}
+
+	var missing []string
+	if !hasCreated {
+		missing = append(missing, "created_at")
+	}
+
+	if !hasUpdated {
+		missing = append(missing, "updated_at")
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []Issue{{Message: fmt.Sprintf("table %s has no %s", db.TableNames.Original, strings.Join(missing, ", "))}}
+}
+
+func checkUnindexedForeignKey(db *model.Database) []Issue {
+	candidates := make(map[string]bool)
+	for _, foreignKey := range db.ForeignKeys {
+		if len(foreignKey.Columns) > 0 {
+			candidates[foreignKey.Columns[0]] = true
+		}
+	}
+
+	// Колонки вида user_id считаем внешними ключами, даже если constraint не объявлен.
+	for _, column := range db.Columns {
+		if !column.IsPrimaryKey && strings.HasSuffix(naming.ToSnakeCase(column.OriginalName), "_id") {
+			candidates[column.OriginalName] = true
+		}
+	}
+
+	var issues []Issue
+	for _, column := range db.Columns {
+		if candidates[column.OriginalName] && !db.IsIndexed(column.OriginalName) {
+			issues = append(issues, columnIssue(column, "foreign key column %s has no index", column.OriginalName))
+		}
+	}
+
+	return issues
+}
+
+func columnIssue(column model.Column, format string, args ...any) Issue {
+	return Issue{
+		Column:  column.OriginalName,
+		File:    column.SourceFile,
+		Line:    column.LineNumber,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
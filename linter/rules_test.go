@@ -0,0 +1,97 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func ruleByID(t *testing.T, id string) Rule {
+	t.Helper()
+
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+
+	t.Fatalf("rule %s not found", id)
+
+	return Rule{}
+}
+
+func table(name string, columns ...model.Column) *model.Database {
+	return &model.Database{TableNames: model.TableNames{Original: name}, Columns: columns}
+}
+
+var (
+	idColumn        = model.Column{OriginalName: "id", Type: "int", SQLType: "INT", IsPrimaryKey: true}
+	createdAtColumn = model.Column{OriginalName: "created_at", Type: "time.Time", SQLType: "DATETIME"}
+	updatedAtColumn = model.Column{OriginalName: "updated_at", Type: "time.Time", SQLType: "DATETIME"}
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule   string
+		db     *model.Database
+		issues int
+	}{
+		{rule: "missing-primary-key", db: table("logs", createdAtColumn), issues: 1},
+		{rule: "missing-primary-key", db: table("logs", idColumn), issues: 0},
+		{
+			rule: "missing-primary-key",
+			db: &model.Database{
+				TableNames: model.TableNames{Original: "tags"},
+				Columns:    []model.Column{{OriginalName: "name", Type: "string"}},
+				Indexes:    []model.Index{{Name: "PRIMARY", Columns: []string{"name"}, IsPrimary: true}},
+			},
+			issues: 0,
+		},
+		{rule: "nullable-boolean", db: table("users", model.Column{OriginalName: "active", Type: "bool", IsNull: true}), issues: 1},
+		{
+			rule:   "nullable-boolean",
+			db:     table("users", model.Column{OriginalName: "active", Type: "int", SQLType: "TINYINT(1)", IsNull: true}),
+			issues: 1,
+		},
+		{rule: "nullable-boolean", db: table("users", model.Column{OriginalName: "active", Type: "bool"}), issues: 0},
+		{rule: "float-money", db: table("orders", model.Column{OriginalName: "total_price", SQLType: "DOUBLE"}), issues: 1},
+		{rule: "float-money", db: table("orders", model.Column{OriginalName: "amount", Type: "float64"}), issues: 1},
+		{rule: "float-money", db: table("orders", model.Column{OriginalName: "price", SQLType: "DECIMAL(10,2)"}), issues: 0},
+		{rule: "float-money", db: table("points", model.Column{OriginalName: "latitude", SQLType: "DOUBLE"}), issues: 0},
+		{rule: "enum-without-default", db: table("users", model.Column{OriginalName: "role", Type: "enum"}), issues: 1},
+		{
+			rule:   "enum-without-default",
+			db:     table("users", model.Column{OriginalName: "role", Type: "enum", DefaultValue: "user"}),
+			issues: 0,
+		},
+		{rule: "inconsistent-naming", db: table("UserRoles", model.Column{OriginalName: "roleID"}), issues: 2},
+		{rule: "inconsistent-naming", db: table("user_roles", model.Column{OriginalName: "role_id"}), issues: 0},
+		{rule: "missing-timestamps", db: table("users", createdAtColumn), issues: 1},
+		{rule: "missing-timestamps", db: table("users", createdAtColumn, updatedAtColumn), issues: 0},
+		{rule: "unindexed-foreign-key", db: table("orders", idColumn, model.Column{OriginalName: "user_id"}), issues: 1},
+		{
+			rule: "unindexed-foreign-key",
+			db: &model.Database{
+				TableNames:  model.TableNames{Original: "orders"},
+				Columns:     []model.Column{idColumn, {OriginalName: "buyer"}},
+				ForeignKeys: []model.ForeignKey{{Columns: []string{"buyer"}, ReferencedTable: "users"}},
+			},
+			issues: 1,
+		},
+		{
+			rule: "unindexed-foreign-key",
+			db: &model.Database{
+				TableNames: model.TableNames{Original: "orders"},
+				Columns:    []model.Column{idColumn, {OriginalName: "user_id"}},
+				Indexes:    []model.Index{{Name: "idx_user", Columns: []string{"user_id", "id"}}},
+			},
+			issues: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		if issues := ruleByID(t, tt.rule).Check(tt.db); len(issues) != tt.issues {
+			t.Errorf("%s on %s: %d issue(s) %+v, want %d", tt.rule, tt.db.TableNames.Original, len(issues), issues, tt.issues)
+		}
+	}
+}
@@ -1,102 +1,374 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
 
 	"github.com/FireAnomaly/go-generator-repository/cli"
+	"github.com/FireAnomaly/go-generator-repository/config"
+	"github.com/FireAnomaly/go-generator-repository/generator"
 	"github.com/FireAnomaly/go-generator-repository/model"
 	"github.com/FireAnomaly/go-generator-repository/parsers/mysql"
 	"github.com/FireAnomaly/go-generator-repository/templater"
 )
 
 var (
-	migrationPathInput = flag.String("in", "", "Path to the migration files (example: /examples)")
-	savePathInput      = flag.String("out", "", "Path to save generated models (example: /examples/output)")
+	migrationPathInput = flag.String("in", "", "Path to the migration files, - to read one migration from stdin (example: ./examples)")
+	savePathInput      = flag.String("out", "", "Path to save generated models (example: ./examples/output)")
 	isLogOutput        = flag.Bool("log", false, "Enable detailed logging")
 	logLevel           = zap.LevelFlag("loglevel", zapcore.InfoLevel, "Set the logging level")
-)
-
-var (
-	parser          FileParser
-	tableManager    TableManager
-	templateManager TemplaterManager
+	nonInteractive     = flag.Bool("non-interactive", false, "Select tables from flags instead of the interactive CLI (default when stdin is not a terminal)")
+	tablesInput        = flag.String("tables", "", "Comma separated table patterns to generate, all tables when empty (example: users,audit_*)")
+	excludeInput       = flag.String("exclude", "", "Comma separated table or table.column patterns to skip (example: audit_*,users.password)")
+	configPathInput    = flag.String("config", "", "Path to the config file (default: gen.yaml in the working directory or its parents)")
+	packageNameInput   = flag.String("package", "", "Package name of generated models (default: last segment of -out)")
+	layoutInput        = flag.String("layout", "", "Package layout: flat (one package), schema (package per schema) or directory (package per migration subdirectory)")
+	importPathInput    = flag.String("import-path", "", "Go import path of the -out package (example: github.com/acme/app/internal/models)")
+	templatesInput     = flag.String("templates", "", "Directory with custom templates overriding or adding to the built-in ones")
+	pruneStale         = flag.Bool("prune", false, "Delete previously generated files of tables that are no longer generated (without it they are only listed)")
+	checkMode          = flag.Bool("check", false, "Do not write files: print a diff against -out and exit with code 1 if it is out of date")
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+
+			return
+		}
+	}
+
 	logger := zap.NewNop()
 
 	flag.Parse()
 
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		log.Fatal("Failed to get working directory:", err)
+	}
+
+	cfg, err := loadConfig(workDir)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	if !setFlags["log"] {
+		*isLogOutput = cfg.Log.Enabled
+	}
+
+	if !setFlags["loglevel"] && cfg.Log.Level != "" {
+		if err = logLevel.Set(cfg.Log.Level); err != nil {
+			log.Fatal("Invalid log level in config:", err)
+		}
+	}
+
 	if *isLogOutput {
-		var err error
-		logger, err = NewLogger()
+		logger, err = NewLogger(*logLevel)
 		if err != nil {
 			log.Fatal("Failed to create logger:", err)
 		}
 	}
 
-	if migrationPathInput == nil || *migrationPathInput == "" {
+	logger.Debug("Config", zap.String("path", cfg.Path()))
+
+	migrationPath := cfg.ResolvePath(cfg.Input)
+	if setFlags["in"] {
+		migrationPath = *migrationPathInput
+	}
+
+	savePath := cfg.ResolvePath(cfg.Output.Path)
+	if setFlags["out"] {
+		savePath = *savePathInput
+	}
+
+	if migrationPath == "" {
 		log.Fatal("Migration path is required")
 	}
 
-	if savePathInput == nil || *savePathInput == "" {
+	if savePath == "" {
 		log.Fatal("Save path is required")
 	}
 
-	workDir, err := os.Getwd()
-	if err != nil {
-		logger.Fatal("Failed to get working directory", zap.Error(err))
+	logger.Info("Paths ", zap.String("migration", migrationPath), zap.String("save", savePath))
+
+	selection := cli.Selection{Tables: cfg.Tables, ExcludeTables: cfg.Exclude.Tables, ExcludeColumns: cfg.Exclude.Columns}
+	if setFlags["tables"] || setFlags["exclude"] {
+		flagSelection := parseSelection(*tablesInput, *excludeInput)
+		if setFlags["tables"] {
+			selection.Tables = flagSelection.Tables
+		}
+
+		if setFlags["exclude"] {
+			selection.ExcludeTables, selection.ExcludeColumns = flagSelection.ExcludeTables, flagSelection.ExcludeColumns
+		}
 	}
 
-	savePath := workDir + *savePathInput
-	migrationPath := workDir + *migrationPathInput
+	templaterOptions := templater.Options{
+		PackageName:   cfg.Output.Package,
+		JSONTags:      cfg.Tags.JSON,
+		DisableDBTags: !cfg.IsDBTagEnabled(),
+		Generators:    cfg.Generators,
+		TemplateDir:   cfg.ResolvePath(cfg.Templates),
+		Layout:        cfg.Output.Layout,
+		FileName:      cfg.Output.FileName,
+		ImportPath:    cfg.Output.ImportPath,
+	}
+	if setFlags["package"] {
+		templaterOptions.PackageName = *packageNameInput
+	}
 
-	logger.Info("Paths ", zap.String("migration", migrationPath), zap.String("save", savePath))
+	if setFlags["layout"] {
+		templaterOptions.Layout = *layoutInput
+	}
 
-	parser = mysql.NewParser(migrationPath, logger)
-	databases, err := parser.GetDatabasesFromMigrations(migrationPath)
+	if setFlags["import-path"] {
+		templaterOptions.ImportPath = *importPathInput
+	}
+
+	if setFlags["templates"] {
+		templaterOptions.TemplateDir = *templatesInput
+	}
+
+	interactive := !*nonInteractive && !*checkMode && cfg.IsInteractive() && term.IsTerminal(int(os.Stdin.Fd()))
+
+	generatorOptions := generator.Options{
+		Dialect:   cfg.Dialect,
+		Config:    cfg,
+		Templater: templaterOptions,
+		OutputDir: savePath,
+		Plugins:   cfg.ResolvedPlugins(),
+		Logger:    logger,
+		Select: func(_ context.Context, databases []*model.Database) error {
+			if !interactive {
+				logger.Info("Running in non-interactive mode")
+
+				return cli.NewNonInteractive(logger, databases, selection).ManageTableByUser()
+			}
+
+			return manageInteractive(logger, cfg, workDir, databases, selection, templater.NewTemplater(logger, templaterOptions), savePath)
+		},
+	}
+	if migrationPath == stdinPath {
+		generatorOptions.Reader = os.Stdin
+	} else {
+		generatorOptions.Input = os.DirFS(migrationPath)
+	}
+
+	var checkOutput *templater.MemoryOutput
+	if *checkMode {
+		checkOutput = templater.NewMemoryOutput()
+		generatorOptions.Output = checkOutput
+	}
+
+	generatorOptions.Prune = *pruneStale
+
+	result, err := generator.Generate(context.Background(), generatorOptions)
 	if err != nil {
-		logger.Fatal("Failed to get migrations", zap.Error(err))
+		logger.Fatal("Failed to generate models", zap.Error(err))
 		panic(err)
 	}
 
-	tableManager = cli.NewTableWriterOnCLI(logger, databases)
-	err = tableManager.ManageTableByUser()
+	printWarnings(result.Warnings)
+
+	if *checkMode {
+		if !check(checkOutput, result.Orphans, savePath) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	reportOrphans(result)
+}
+
+// check печатает разницу между сгенерированным кодом и файлами в savePath и сообщает, совпадают ли они.
+func check(output *templater.MemoryOutput, orphans []string, savePath string) bool {
+	diffs, err := generator.Compare(output, savePath)
 	if err != nil {
-		logger.Fatal("Failed to manage table by user", zap.Error(err))
-		panic(err)
+		log.Fatal("Failed to compare generated code:", err)
+	}
+
+	for _, diff := range diffs {
+		fmt.Print(diff.Unified)
+	}
+
+	for _, orphan := range orphans {
+		fmt.Printf("stale generated file: %s\n", filepath.Join(savePath, orphan))
+	}
+
+	if len(diffs) > 0 || len(orphans) > 0 {
+		fmt.Fprintf(os.Stderr, "%d generated file(s) in %s are out of date, run the generator again\n",
+			len(diffs)+len(orphans), savePath)
+
+		return false
 	}
 
-	templateManager = templater.NewTemplater(logger)
-	err = templateManager.SaveModels(databases, savePath)
+	return true
+}
+
+func reportOrphans(result *generator.Result) {
+	if len(result.Orphans) == 0 {
+		return
+	}
+
+	if result.Pruned {
+		fmt.Printf("Removed %d stale generated file(s): %s\n", len(result.Orphans), strings.Join(result.Orphans, ", "))
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%d generated file(s) are no longer generated, run with -prune to delete them:\n", len(result.Orphans))
+	for _, orphan := range result.Orphans {
+		fmt.Fprintf(os.Stderr, "  %s\n", orphan)
+	}
+}
+
+// manageInteractive показывает TUI и сохраняет сделанный в нём выбор в файл конфигурации.
+func manageInteractive(logger *zap.Logger, cfg *config.Config, workDir string, databases []*model.Database,
+	selection cli.Selection, previewTemplater *templater.Templater, savePath string,
+) error {
+	// Выбор из конфига применяется заранее, чтобы TUI показал эти таблицы отключенными.
+	if err := cli.ApplySelection(databases, selection); err != nil {
+		return err
+	}
+
+	previewer := cli.PreviewFunc(func(db *model.Database) (string, error) {
+		code, err := previewTemplater.Render(db, savePath)
+
+		return string(code), err
+	})
+
+	snapshot := config.TakeSnapshot(databases)
+
+	err := cli.NewTableWriterOnCLI(logger, databases, cli.Options{Previewer: previewer}).ManageTableByUser()
 	if err != nil {
-		logger.Fatal("Failed to create DB model", zap.Error(err))
-		panic(err)
+		return err
+	}
+
+	if !cfg.Update(snapshot, databases) {
+		return nil
+	}
+
+	configPath := cfg.Path()
+	if configPath == "" {
+		configPath = filepath.Join(workDir, config.FileNames[0])
+	}
+
+	if err = cfg.Save(configPath); err != nil {
+		return fmt.Errorf("failed to save selection to config: %w", err)
+	}
+
+	logger.Info("Selection saved to config", zap.String("path", configPath))
+
+	return nil
+}
+
+// stdinPath значение -in для чтения одной миграции из stdin.
+const stdinPath = "-"
+
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
 	}
 }
 
-// FileParser Интерфейс для возможного кастомного парсера.
-type FileParser interface {
-	GetDatabasesFromMigrations(migrationPath string) ([]*model.Database, error)
+// parseMigrations разбирает миграции из директории или, для "-", одну миграцию из stdin так же, как генерация.
+// Пути к файлам миграций считаются от рабочей директории, чтобы отчёты указывали на файлы миграций.
+func parseMigrations(migrationPath string, logger *zap.Logger) ([]*model.Database, *mysql.Parser, error) {
+	parser := mysql.NewParser(logger)
+	options := generator.Options{Parser: parser, Logger: logger}
+	if migrationPath == stdinPath {
+		options.Reader = os.Stdin
+	} else {
+		options.Input = os.DirFS(migrationPath)
+	}
+
+	databases, warnings, err := generator.ParseWithWarnings(context.Background(), options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	printWarnings(warnings)
+
+	sourcePath := func(name string) string {
+		if migrationPath == stdinPath {
+			return "stdin"
+		}
+
+		return filepath.Join(migrationPath, filepath.FromSlash(name))
+	}
+
+	for _, db := range databases {
+		for i, name := range db.SourceFiles {
+			db.SourceFiles[i] = sourcePath(name)
+		}
+
+		for i := range db.Columns {
+			if db.Columns[i].SourceFile != "" {
+				db.Columns[i].SourceFile = sourcePath(db.Columns[i].SourceFile)
+			}
+		}
+	}
+
+	return databases, parser, nil
+}
+
+// loadConfig загружает конфигурацию из -config или ищет её от рабочей директории. Без файла возвращает пустую.
+func loadConfig(workDir string) (*config.Config, error) {
+	if *configPathInput != "" {
+		return config.Load(*configPathInput)
+	}
+
+	cfg, err := config.Discover(workDir)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return &config.Config{}, nil
+	}
+
+	return cfg, err
 }
 
-type TableManager interface {
-	ManageTableByUser() error
+// parseSelection разделяет -exclude на таблицы и колонки: шаблоны с точкой относятся к колонкам.
+func parseSelection(tables, exclude string) cli.Selection {
+	selection := cli.Selection{Tables: splitList(tables)}
+	for _, pattern := range splitList(exclude) {
+		if strings.Contains(pattern, ".") {
+			selection.ExcludeColumns = append(selection.ExcludeColumns, pattern)
+		} else {
+			selection.ExcludeTables = append(selection.ExcludeTables, pattern)
+		}
+	}
+
+	return selection
 }
 
-type TemplaterManager interface {
-	SaveModels(databases []*model.Database, savePath string) error
+// commands подкоманды, у каждой свой набор флагов. Без подкоманды запускается генерация моделей.
+var commands = map[string]func(args []string) error{
+	"migrate": runMigrate,
+	"init":    runInit,
+	"lint":    runLint,
 }
 
-func NewLogger() (*zap.Logger, error) {
+func NewLogger(level zapcore.Level) (*zap.Logger, error) {
 	config := zap.NewDevelopmentConfig()
-	config.Level.SetLevel(*logLevel)
+	config.Level.SetLevel(level)
 	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	return config.Build()
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FireAnomaly/go-generator-repository/migrator"
+)
+
+// runMigrate генерирует миграцию от текущих миграций к желаемой схеме (IR файл или Go структуры).
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	migrationPathInput := flags.String("in", "", "Path to the current migration files, - to read one migration from stdin (example: ./examples)")
+	desiredPathInput := flags.String("desired", "", "Path to the desired schema: IR .json file, Go file or directory with Go structs")
+	savePathInput := flags.String("out", "", "Path to save the new migration (default: same as -in)")
+	title := flags.String("name", "schema_diff", "Title of the migration used in file names")
+	dryRun := flags.Bool("dry-run", false, "Print the migration instead of writing files")
+	dumpIR := flags.String("dump-ir", "", "Write the current schema as an IR .json file to start editing the desired schema from")
+	isLogOutput := flags.Bool("log", false, "Enable detailed logging")
+	logLevel := zapcore.InfoLevel
+	flags.Var(&logLevel, "loglevel", "Set the logging level")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	logger := zap.NewNop()
+	if *isLogOutput {
+		var err error
+		logger, err = NewLogger(logLevel)
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+	}
+
+	if *migrationPathInput == "" || (*desiredPathInput == "" && *dumpIR == "") {
+		return fmt.Errorf("-in and -desired (or -dump-ir) are required")
+	}
+
+	if *savePathInput == "" && *migrationPathInput == stdinPath && !*dryRun && *dumpIR == "" {
+		return fmt.Errorf("-out is required when the migration is read from stdin")
+	}
+
+	if *savePathInput == "" {
+		savePathInput = migrationPathInput
+	}
+
+	current, mysqlParser, err := parseMigrations(*migrationPathInput, logger)
+	if err != nil {
+		return fmt.Errorf("failed to get migrations: %w", err)
+	}
+
+	schemaMigrator := migrator.NewMigrator(mysqlParser, logger)
+	if *dumpIR != "" {
+		return schemaMigrator.SaveSchema(current, *dumpIR)
+	}
+
+	desired, err := schemaMigrator.LoadSchema(*desiredPathInput)
+	if err != nil {
+		return fmt.Errorf("failed to load desired schema: %w", err)
+	}
+
+	migration := migrator.Diff(current, desired)
+	if migration.IsEmpty() {
+		fmt.Println("Schema is up to date, nothing to migrate")
+
+		return nil
+	}
+
+	if *dryRun {
+		fmt.Print(migration.String())
+
+		return nil
+	}
+
+	upPath, downPath, err := schemaMigrator.SaveMigration(migration, *savePathInput, *title)
+	if err != nil {
+		return fmt.Errorf("failed to save migration: %w", err)
+	}
+
+	fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+
+	return nil
+}
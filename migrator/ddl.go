@@ -0,0 +1,182 @@
+package migrator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// sqlTypesByGoType используется, когда у колонки нет исходного SQL типа (например, схема пришла из Go структуры).
+var sqlTypesByGoType = map[string]string{
+	"int":       "INT",
+	"uint":      "INT UNSIGNED",
+	"float32":   "FLOAT",
+	"float64":   "DOUBLE",
+	"string":    "TEXT",
+	"bool":      "BOOL",
+	"time.Time": "DATETIME",
+	"[]byte":    "BLOB",
+}
+
+var unquotedDefaults = map[string]bool{
+	"NULL":              true,
+	"TRUE":              true,
+	"FALSE":             true,
+	"CURRENT_TIMESTAMP": true,
+	"CURRENT_DATE":      true,
+	"CURRENT_TIME":      true,
+	"NOW()":             true,
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func columnSQLType(column model.Column) string {
+	if column.SQLType != "" {
+		return column.SQLType
+	}
+
+	if column.IsEnum() {
+		values := make([]string, 0, len(column.EnumValues))
+		for _, value := range column.EnumValues {
+			values = append(values, quoteString(value))
+		}
+
+		return "ENUM(" + strings.Join(values, ", ") + ")"
+	}
+
+	if sqlType, ok := sqlTypesByGoType[column.Type]; ok {
+		return sqlType
+	}
+
+	return "TEXT"
+}
+
+func defaultLiteral(value any) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+
+		return "0"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		if unquotedDefaults[strings.ToUpper(v)] {
+			return strings.ToUpper(v)
+		}
+
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return v
+		}
+
+		return quoteString(v)
+	default:
+		return quoteString(fmt.Sprint(v))
+	}
+}
+
+// columnDefinition определение колонки для CREATE TABLE вместе с ключами, объявленными в её строке.
+func columnDefinition(column model.Column) string {
+	definition := columnSpec(column)
+	if column.IsUnique {
+		definition += " UNIQUE"
+	}
+
+	if column.IsPrimaryKey {
+		definition += " PRIMARY KEY"
+	}
+
+	return definition
+}
+
+// columnSpec определение колонки без ключей для ADD и MODIFY COLUMN: MODIFY не удаляет существующие ключи,
+// а повторный PRIMARY KEY или UNIQUE в нём дал бы ошибку или лишний индекс. Ключи меняются отдельно, см. diffKeys.
+func columnSpec(column model.Column) string {
+	definition := quoteIdentifier(column.OriginalName) + " " + columnSQLType(column)
+	if !column.IsNull {
+		definition += " NOT NULL"
+	}
+
+	if column.DefaultValue != nil {
+		definition += " DEFAULT " + defaultLiteral(column.DefaultValue)
+	}
+
+	if column.IsAutoIncrement {
+		definition += " AUTO_INCREMENT"
+	}
+
+	return definition
+}
+
+func createTable(database *model.Database) string {
+	definitions := make([]string, 0, len(database.Columns))
+	for _, column := range database.Columns {
+		definitions = append(definitions, "    "+columnDefinition(column))
+	}
+
+	for _, index := range database.Indexes {
+		definitions = append(definitions, "    "+indexDefinition(index))
+	}
+
+	for _, foreignKey := range database.ForeignKeys {
+		definitions = append(definitions, "    "+foreignKeyDefinition(foreignKey))
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s\n(\n%s\n);",
+		quoteIdentifier(database.TableNames.Original), strings.Join(definitions, ",\n"))
+}
+
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdentifier(name))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func indexDefinition(index model.Index) string {
+	switch {
+	case index.IsPrimary:
+		return "PRIMARY KEY (" + quoteIdentifiers(index.Columns) + ")"
+	case index.IsUnique:
+		return strings.TrimSpace("UNIQUE KEY "+optionalIdentifier(index.Name)) + " (" + quoteIdentifiers(index.Columns) + ")"
+	default:
+		return strings.TrimSpace("KEY "+optionalIdentifier(index.Name)) + " (" + quoteIdentifiers(index.Columns) + ")"
+	}
+}
+
+func foreignKeyDefinition(foreignKey model.ForeignKey) string {
+	definition := "FOREIGN KEY (" + quoteIdentifiers(foreignKey.Columns) + ") REFERENCES " +
+		quoteIdentifier(foreignKey.ReferencedTable) + " (" + quoteIdentifiers(foreignKey.ReferencedColumns) + ")"
+	if foreignKey.Name != "" {
+		definition = "CONSTRAINT " + quoteIdentifier(foreignKey.Name) + " " + definition
+	}
+
+	return definition
+}
+
+func optionalIdentifier(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return quoteIdentifier(name)
+}
+
+func dropTable(database *model.Database) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", quoteIdentifier(database.TableNames.Original))
+}
+
+func alterTable(tableName string, clauses []string) string {
+	return fmt.Sprintf("ALTER TABLE %s\n    %s;", quoteIdentifier(tableName), strings.Join(clauses, ",\n    "))
+}
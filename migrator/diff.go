@@ -0,0 +1,246 @@
+package migrator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// Migration содержит выражения для перехода к желаемой схеме (Up) и для отката (Down).
+type Migration struct {
+	Up   []string
+	Down []string
+}
+
+func (m *Migration) IsEmpty() bool {
+	return len(m.Up) == 0
+}
+
+func (m *Migration) add(up, down string) {
+	m.Up = append(m.Up, up)
+	m.Down = append([]string{down}, m.Down...)
+}
+
+// Diff сравнивает текущую схему из миграций с желаемой и возвращает миграцию между ними.
+// Таблицы и колонки сопоставляются по оригинальному имени.
+func Diff(current, desired []*model.Database) *Migration {
+	migration := &Migration{}
+
+	currentByName := make(map[string]*model.Database, len(current))
+	for _, db := range current {
+		currentByName[db.TableNames.Original] = db
+	}
+
+	desiredNames := make(map[string]bool, len(desired))
+	for _, db := range desired {
+		desiredNames[db.TableNames.Original] = true
+
+		currentDB, ok := currentByName[db.TableNames.Original]
+		if !ok {
+			migration.add(createTable(db), dropTable(db))
+
+			continue
+		}
+
+		up, down := diffColumns(currentDB.Columns, db.Columns)
+		up = append(up, diffKeys(currentDB, db)...)
+		down = append(down, diffKeys(db, currentDB)...)
+		if len(up) > 0 {
+			migration.add(alterTable(db.TableNames.Original, up), alterTable(db.TableNames.Original, down))
+		}
+	}
+
+	for _, db := range current {
+		if desiredNames[db.TableNames.Original] {
+			continue
+		}
+
+		migration.add(dropTable(db), createTable(db))
+	}
+
+	return migration
+}
+
+func diffColumns(current, desired []model.Column) (up []string, down []string) {
+	currentByName := make(map[string]model.Column, len(current))
+	for _, column := range current {
+		currentByName[column.OriginalName] = column
+	}
+
+	var downDrops, downModifies, downAdds []string
+
+	desiredNames := make(map[string]bool, len(desired))
+	for i, column := range desired {
+		desiredNames[column.OriginalName] = true
+
+		currentColumn, ok := currentByName[column.OriginalName]
+		if !ok {
+			up = append(up, "ADD COLUMN "+columnSpec(column)+columnPosition(desired, i))
+			downDrops = append(downDrops, "DROP COLUMN "+quoteIdentifier(column.OriginalName))
+
+			continue
+		}
+
+		if !isSameColumn(currentColumn, column) {
+			up = append(up, "MODIFY COLUMN "+columnSpec(column))
+			downModifies = append(downModifies, "MODIFY COLUMN "+columnSpec(currentColumn))
+		}
+	}
+
+	for i, column := range current {
+		if desiredNames[column.OriginalName] {
+			continue
+		}
+
+		up = append(up, "DROP COLUMN "+quoteIdentifier(column.OriginalName))
+		downAdds = append(downAdds, "ADD COLUMN "+columnSpec(column)+columnPosition(current, i))
+	}
+
+	down = append(append(downDrops, downModifies...), downAdds...)
+
+	return up, down
+}
+
+// diffKeys выражения, которые меняют первичный ключ, индексы, внешние ключи и уникальность колонок таблицы from
+// на ключи to. Колонки к этому моменту уже добавлены или удалены diffColumns; ключ удалённой колонки MySQL удаляет сам.
+func diffKeys(from, to *model.Database) []string {
+	dropForeignKeys, addForeignKeys := diffForeignKeys(from, to)
+	dropIndexes, addIndexes := diffIndexes(from, to)
+
+	clauses := append(dropForeignKeys, dropIndexes...)
+
+	fromPrimaryKey, toPrimaryKey := from.PrimaryKey(), to.PrimaryKey()
+	if !slices.Equal(fromPrimaryKey, toPrimaryKey) {
+		if slices.ContainsFunc(fromPrimaryKey, func(name string) bool { return hasColumn(to, name) }) {
+			clauses = append(clauses, "DROP PRIMARY KEY")
+		}
+
+		if len(toPrimaryKey) > 0 {
+			clauses = append(clauses, "ADD PRIMARY KEY ("+quoteIdentifiers(toPrimaryKey)+")")
+		}
+	}
+
+	fromUnique := make(map[string]bool, len(from.Columns))
+	for _, column := range from.Columns {
+		fromUnique[column.OriginalName] = column.IsUnique
+	}
+
+	for _, column := range from.Columns {
+		if column.IsUnique && slices.ContainsFunc(to.Columns, func(c model.Column) bool {
+			return c.OriginalName == column.OriginalName && !c.IsUnique
+		}) {
+			// Индекс UNIQUE из строки колонки MySQL называет именем колонки.
+			clauses = append(clauses, "DROP INDEX "+quoteIdentifier(column.OriginalName))
+		}
+	}
+
+	for _, column := range to.Columns {
+		if column.IsUnique && !fromUnique[column.OriginalName] {
+			clauses = append(clauses, "ADD UNIQUE KEY "+quoteIdentifier(column.OriginalName)+
+				" ("+quoteIdentifier(column.OriginalName)+")")
+		}
+	}
+
+	return append(append(clauses, addIndexes...), addForeignKeys...)
+}
+
+// diffIndexes сравнивает индексы таблиц, кроме первичного ключа, по определению: изменённый индекс
+// удаляется и создаётся заново.
+func diffIndexes(from, to *model.Database) (drops, adds []string) {
+	fromDefinitions := make(map[string]bool, len(from.Indexes))
+	for _, index := range from.Indexes {
+		fromDefinitions[indexDefinition(index)] = true
+	}
+
+	toDefinitions := make(map[string]bool, len(to.Indexes))
+	for _, index := range to.Indexes {
+		toDefinitions[indexDefinition(index)] = true
+	}
+
+	for _, index := range from.Indexes {
+		if index.IsPrimary || toDefinitions[indexDefinition(index)] ||
+			!slices.ContainsFunc(index.Columns, func(name string) bool { return hasColumn(to, name) }) {
+			continue
+		}
+
+		drops = append(drops, "DROP INDEX "+quoteIdentifier(index.DatabaseName()))
+	}
+
+	for _, index := range to.Indexes {
+		if !index.IsPrimary && !fromDefinitions[indexDefinition(index)] {
+			adds = append(adds, "ADD "+indexDefinition(index))
+		}
+	}
+
+	return drops, adds
+}
+
+// diffForeignKeys сравнивает внешние ключи таблиц по определению, как diffIndexes.
+func diffForeignKeys(from, to *model.Database) (drops, adds []string) {
+	fromDefinitions := make(map[string]bool, len(from.ForeignKeys))
+	for _, foreignKey := range from.ForeignKeys {
+		fromDefinitions[foreignKeyDefinition(foreignKey)] = true
+	}
+
+	toDefinitions := make(map[string]bool, len(to.ForeignKeys))
+	for _, foreignKey := range to.ForeignKeys {
+		toDefinitions[foreignKeyDefinition(foreignKey)] = true
+	}
+
+	for i, foreignKey := range from.ForeignKeys {
+		if !toDefinitions[foreignKeyDefinition(foreignKey)] {
+			drops = append(drops, "DROP FOREIGN KEY "+quoteIdentifier(from.ForeignKeyName(i)))
+		}
+	}
+
+	for _, foreignKey := range to.ForeignKeys {
+		if !fromDefinitions[foreignKeyDefinition(foreignKey)] {
+			adds = append(adds, "ADD "+foreignKeyDefinition(foreignKey))
+		}
+	}
+
+	return drops, adds
+}
+
+func hasColumn(database *model.Database, name string) bool {
+	return slices.ContainsFunc(database.Columns, func(c model.Column) bool { return c.OriginalName == name })
+}
+
+func columnPosition(columns []model.Column, index int) string {
+	if index == 0 {
+		return " FIRST"
+	}
+
+	return " AFTER " + quoteIdentifier(columns[index-1].OriginalName)
+}
+
+// isSameColumn сравнивает определения колонок без ключей, ключи сравнивает diffKeys.
+func isSameColumn(a, b model.Column) bool {
+	if a.IsNull != b.IsNull || a.IsAutoIncrement != b.IsAutoIncrement || !slices.Equal(a.EnumValues, b.EnumValues) {
+		return false
+	}
+
+	if normalizeSQLType(columnSQLType(a)) != normalizeSQLType(columnSQLType(b)) {
+		return false
+	}
+
+	if (a.DefaultValue == nil) != (b.DefaultValue == nil) {
+		return false
+	}
+
+	return a.DefaultValue == nil || defaultLiteral(a.DefaultValue) == defaultLiteral(b.DefaultValue)
+}
+
+func normalizeSQLType(sqlType string) string {
+	sqlType = strings.ToUpper(strings.Join(strings.Fields(sqlType), " "))
+	sqlType = strings.ReplaceAll(sqlType, " (", "(")
+
+	return strings.ReplaceAll(sqlType, ", ", ",")
+}
+
+// String возвращает Up и Down части миграции, удобно для вывода в консоль.
+func (m *Migration) String() string {
+	return fmt.Sprintf("-- up\n%s\n\n-- down\n%s\n", strings.Join(m.Up, "\n\n"), strings.Join(m.Down, "\n\n"))
+}
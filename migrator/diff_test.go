@@ -0,0 +1,113 @@
+package migrator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func ordersTable(columns []model.Column, indexes []model.Index, foreignKeys []model.ForeignKey) *model.Database {
+	return &model.Database{
+		TableNames:  model.TableNames{Original: "orders", CamelCase: "Orders"},
+		Columns:     columns,
+		Indexes:     indexes,
+		ForeignKeys: foreignKeys,
+	}
+}
+
+var (
+	orderID     = model.Column{OriginalName: "id", Type: "int", SQLType: "INT", IsPrimaryKey: true, IsAutoIncrement: true}
+	orderUserID = model.Column{OriginalName: "user_id", Type: "int", SQLType: "INT"}
+	orderNote   = model.Column{OriginalName: "note", Type: "string", SQLType: "VARCHAR(255)", IsNull: true}
+)
+
+func TestDiffUpToDate(t *testing.T) {
+	current := []*model.Database{ordersTable([]model.Column{orderID, orderUserID}, nil, nil)}
+	desired := []*model.Database{ordersTable([]model.Column{orderID, orderUserID}, nil, nil)}
+
+	if migration := Diff(current, desired); !migration.IsEmpty() {
+		t.Errorf("Diff() = %s, want empty migration", migration)
+	}
+}
+
+func TestDiffTables(t *testing.T) {
+	users := &model.Database{
+		TableNames: model.TableNames{Original: "users"},
+		Columns:    []model.Column{orderID},
+	}
+
+	migration := Diff([]*model.Database{users}, []*model.Database{ordersTable([]model.Column{orderID}, nil, nil)})
+
+	wantUp := []string{
+		"CREATE TABLE IF NOT EXISTS `orders`\n(\n    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY\n);",
+		"DROP TABLE IF EXISTS `users`;",
+	}
+	if !slices.Equal(migration.Up, wantUp) {
+		t.Errorf("Diff().Up = %q, want %q", migration.Up, wantUp)
+	}
+
+	wantDown := []string{
+		"CREATE TABLE IF NOT EXISTS `users`\n(\n    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY\n);",
+		"DROP TABLE IF EXISTS `orders`;",
+	}
+	if !slices.Equal(migration.Down, wantDown) {
+		t.Errorf("Diff().Down = %q, want %q", migration.Down, wantDown)
+	}
+}
+
+func TestDiffColumns(t *testing.T) {
+	nullableUserID := orderUserID
+	nullableUserID.IsNull = true
+
+	current := []*model.Database{ordersTable([]model.Column{orderID, orderUserID, orderNote}, nil, nil)}
+	desired := []*model.Database{ordersTable([]model.Column{orderID, nullableUserID}, nil, nil)}
+
+	migration := Diff(current, desired)
+
+	wantUp := []string{"ALTER TABLE `orders`\n" +
+		"    MODIFY COLUMN `user_id` INT,\n" +
+		"    DROP COLUMN `note`;"}
+	if !slices.Equal(migration.Up, wantUp) {
+		t.Errorf("Diff().Up = %q, want %q", migration.Up, wantUp)
+	}
+
+	wantDown := []string{"ALTER TABLE `orders`\n" +
+		"    MODIFY COLUMN `user_id` INT NOT NULL,\n" +
+		"    ADD COLUMN `note` VARCHAR(255) AFTER `user_id`;"}
+	if !slices.Equal(migration.Down, wantDown) {
+		t.Errorf("Diff().Down = %q, want %q", migration.Down, wantDown)
+	}
+}
+
+func TestDiffIndexesAndForeignKeys(t *testing.T) {
+	columns := []model.Column{orderID, orderUserID}
+	current := []*model.Database{ordersTable(columns,
+		[]model.Index{{Columns: []string{"user_id"}}},
+		[]model.ForeignKey{{Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+	)}
+	desired := []*model.Database{ordersTable(columns,
+		[]model.Index{{Name: "idx_user", Columns: []string{"user_id"}, IsUnique: true}},
+		[]model.ForeignKey{{Name: "fk_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+	)}
+
+	migration := Diff(current, desired)
+
+	wantUp := []string{"ALTER TABLE `orders`\n" +
+		"    DROP FOREIGN KEY `orders_ibfk_1`,\n" +
+		"    DROP INDEX `user_id`,\n" +
+		"    ADD UNIQUE KEY `idx_user` (`user_id`),\n" +
+		"    ADD CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);"}
+	if !slices.Equal(migration.Up, wantUp) {
+		t.Errorf("Diff().Up = %q, want %q", migration.Up, wantUp)
+	}
+
+	wantDown := []string{"ALTER TABLE `orders`\n" +
+		"    DROP FOREIGN KEY `fk_user`,\n" +
+		"    DROP INDEX `idx_user`,\n" +
+		"    ADD KEY (`user_id`),\n" +
+		"    ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);"}
+	if !slices.Equal(migration.Down, wantDown) {
+		t.Errorf("Diff().Down = %q, want %q", migration.Down, wantDown)
+	}
+}
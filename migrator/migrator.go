@@ -0,0 +1,110 @@
+package migrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// timestampVersionLength длина версии вида 20060102150405, короче считаем нумерацию последовательной.
+const timestampVersionLength = 14
+
+var (
+	reMigrationVersion = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+	reTitleSeparators  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type Migrator struct {
+	columnParser ColumnDefinitionParser
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+func NewMigrator(columnParser ColumnDefinitionParser, logger *zap.Logger) *Migrator {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &Migrator{columnParser: columnParser, logger: logger.Named("Migrator: "), now: time.Now}
+}
+
+// SaveMigration записывает миграцию в формате golang-migrate: {version}_{title}.up.sql и {version}_{title}.down.sql.
+// Если в директории уже есть миграции с последовательной нумерацией, продолжает её, иначе использует timestamp.
+func (m *Migrator) SaveMigration(migration *Migration, savePath, title string) (upPath, downPath string, err error) {
+	version, err := m.nextVersion(savePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	baseName := version + "_" + sanitizeTitle(title)
+	upPath = filepath.Join(savePath, baseName+".up.sql")
+	downPath = filepath.Join(savePath, baseName+".down.sql")
+
+	m.logger.Info("Saving migration", zap.String("up", upPath), zap.String("down", downPath))
+
+	if err = os.WriteFile(upPath, []byte(strings.Join(migration.Up, "\n\n")+"\n"), 0o644); err != nil {
+		m.logger.Error("Failed to write up migration", zap.Error(err))
+
+		return "", "", err
+	}
+
+	if err = os.WriteFile(downPath, []byte(strings.Join(migration.Down, "\n\n")+"\n"), 0o644); err != nil {
+		m.logger.Error("Failed to write down migration", zap.Error(err))
+
+		return "", "", err
+	}
+
+	return upPath, downPath, nil
+}
+
+func (m *Migrator) nextVersion(savePath string) (string, error) {
+	entries, err := os.ReadDir(savePath)
+	if err != nil {
+		m.logger.Debug("os.ReadDir error", zap.Error(err))
+
+		return "", fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var (
+		lastVersion uint64
+		width       int
+	)
+	for _, entry := range entries {
+		matches := reMigrationVersion.FindStringSubmatch(entry.Name())
+		if len(matches) < 2 {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if version >= lastVersion {
+			lastVersion, width = version, len(matches[1])
+		}
+	}
+
+	if width == 0 || width >= timestampVersionLength {
+		return m.now().UTC().Format("20060102150405"), nil
+	}
+
+	return fmt.Sprintf("%0*d", width, lastVersion+1), nil
+}
+
+func sanitizeTitle(title string) string {
+	title = strings.ToLower(strings.TrimSpace(title))
+	title = reTitleSeparators.ReplaceAllString(title, "_")
+	title = strings.Trim(title, "_")
+	if title == "" {
+		return "schema_diff"
+	}
+
+	return title
+}
@@ -0,0 +1,274 @@
+package migrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+var (
+	ErrUnsupportedSchema  = errors.New("unsupported desired schema source")
+	ErrNoColumnDefinition = errors.New("no column definition")
+)
+
+// tableAnnotation в doc комментарии структуры задаёт имя таблицы: "// gen:table users".
+const tableAnnotation = "gen:table"
+
+// reGeneratedTable doc комментарий модели, которую сгенерировал templater: "// Users is a row of table users.".
+var reGeneratedTable = regexp.MustCompile(`^\w+ is a row of table (\S+)\.$`)
+
+// sqlAnnotation в doc комментарии поля задаёт определение колонки, так его пишет templater: "// SQL: INT NOT NULL".
+const sqlAnnotation = "SQL:"
+
+// ColumnDefinitionParser разбирает SQL определение колонки из тега `sql` или комментария "// SQL:".
+type ColumnDefinitionParser interface {
+	ParseColumnDefinition(definition string) (model.Column, error)
+}
+
+// LoadSchema читает желаемую схему. Поддерживаются IR файл (.json, сериализованный []*model.Database),
+// а также Go файл или директория с Go файлами, где поля структур размечены тегом `db`,
+// а определение колонки задано тегом `sql` или комментарием "// SQL:".
+func (m *Migrator) LoadSchema(path string) ([]*model.Database, error) {
+	m.logger.Debug("LoadSchema called", zap.String("path", path))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		m.logger.Debug("os.Stat error", zap.Error(err))
+
+		return nil, err
+	}
+
+	switch {
+	case info.IsDir():
+		return m.loadGoSchema(path)
+	case strings.HasSuffix(path, ".json"):
+		return m.loadIRSchema(path)
+	case strings.HasSuffix(path, ".go"):
+		return m.loadGoSchema(path)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSchema, path)
+	}
+}
+
+func (m *Migrator) loadIRSchema(path string) ([]*model.Database, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.logger.Debug("os.ReadFile error", zap.Error(err))
+
+		return nil, err
+	}
+
+	var databases []*model.Database
+	if err = json.Unmarshal(data, &databases); err != nil {
+		m.logger.Debug("json.Unmarshal error", zap.Error(err))
+
+		return nil, fmt.Errorf("failed to decode schema %s: %w", path, err)
+	}
+
+	return databases, nil
+}
+
+// SaveSchema записывает схему в IR файл, который потом можно передать в LoadSchema.
+func (m *Migrator) SaveSchema(databases []*model.Database, path string) error {
+	data, err := json.MarshalIndent(databases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		m.logger.Error("Failed to write schema", zap.Error(err))
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *Migrator) loadGoSchema(path string) ([]*model.Database, error) {
+	paths := []string{path}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		paths, err = filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return nil, fmt.Errorf("error finding go files: %w", err)
+		}
+	}
+
+	fileSet := token.NewFileSet()
+	files := make([]*ast.File, 0, len(paths))
+	for _, goPath := range paths {
+		if strings.HasSuffix(goPath, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fileSet, goPath, nil, parser.ParseComments)
+		if err != nil {
+			m.logger.Debug("parser.ParseFile error", zap.Error(err), zap.String("path", goPath))
+
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	var databases []*model.Database
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = genDecl.Doc
+				}
+
+				database, err := m.structToDatabase(typeSpec.Name.Name, doc, structType)
+				if err != nil {
+					return nil, err
+				}
+
+				if database != nil {
+					databases = append(databases, database)
+				}
+			}
+		}
+	}
+
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("%w: no structs with db tags in %s", ErrUnsupportedSchema, path)
+	}
+
+	return databases, nil
+}
+
+func (m *Migrator) structToDatabase(
+	name string, doc *ast.CommentGroup, structType *ast.StructType,
+) (*model.Database, error) {
+	database := &model.Database{TableNames: model.TableNames{CamelCase: name, Original: name}}
+	if doc != nil {
+		for _, comment := range doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if tableName, ok := strings.CutPrefix(text, tableAnnotation); ok && strings.TrimSpace(tableName) != "" {
+				database.TableNames.Original = strings.TrimSpace(tableName)
+			} else if match := reGeneratedTable.FindStringSubmatch(text); match != nil {
+				database.TableNames.Original = match[1]
+			}
+		}
+	}
+
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+
+		rawTag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		tag := reflect.StructTag(rawTag)
+		columnName, _, _ := strings.Cut(tag.Get("db"), ",")
+		if columnName == "" || columnName == "-" {
+			continue
+		}
+
+		column, err := m.fieldToColumn(columnName, field.Type, fieldDefinition(tag, field.Doc))
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", name, field.Names[0].Name, err)
+		}
+
+		column.CamelCaseName = field.Names[0].Name
+		database.Columns = append(database.Columns, column)
+	}
+
+	if len(database.Columns) == 0 {
+		return nil, nil
+	}
+
+	return database, nil
+}
+
+// fieldDefinition берёт определение колонки из тега `sql`, а без него из комментария "// SQL: ...".
+func fieldDefinition(tag reflect.StructTag, doc *ast.CommentGroup) string {
+	if definition := tag.Get("sql"); definition != "" {
+		return definition
+	}
+
+	if doc == nil {
+		return ""
+	}
+
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if definition, ok := strings.CutPrefix(text, sqlAnnotation); ok {
+			return strings.TrimSpace(definition)
+		}
+	}
+
+	return ""
+}
+
+// fieldToColumn разбирает определение колонки. Тип по Go типу не выводится: TEXT вместо VARCHAR(255)
+// или NOT NULL вместо NULL дали бы ложные MODIFY, поэтому поле без определения считается ошибкой.
+func (m *Migrator) fieldToColumn(columnName string, fieldType ast.Expr, definition string) (model.Column, error) {
+	if definition != "" {
+		return m.columnParser.ParseColumnDefinition(columnName + " " + definition)
+	}
+
+	typeName := exprString(fieldType)
+	if typeName == "" {
+		return model.Column{}, fmt.Errorf("%w: unsupported go type expression, set the sql tag", ErrNoColumnDefinition)
+	}
+
+	return model.Column{}, fmt.Errorf("%w: go type %s, set the sql tag", ErrNoColumnDefinition, typeName)
+}
+
+// exprString печатает тип поля для сообщения об ошибке, для неподдерживаемых выражений возвращает "".
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.BasicLit:
+		return e.Value
+	case *ast.SelectorExpr:
+		if x := exprString(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	case *ast.StarExpr:
+		if x := exprString(e.X); x != "" {
+			return "*" + x
+		}
+	case *ast.ArrayType:
+		elt := exprString(e.Elt)
+		if e.Len == nil && elt != "" {
+			return "[]" + elt
+		}
+
+		if length := exprString(e.Len); length != "" && elt != "" {
+			return "[" + length + "]" + elt
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,84 @@
+package migrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/parsers/mysql"
+)
+
+func writeGoSchema(t *testing.T, source string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "schema.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadSchemaGeneratedModel(t *testing.T) {
+	parser := mysql.NewParser(nil)
+	migrator := NewMigrator(parser, nil)
+
+	current, err := parser.ParseMigration("", []byte(`CREATE TABLE users (
+    id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255),
+    token BINARY(16) NOT NULL
+);`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desired, err := migrator.LoadSchema(writeGoSchema(t, `package models
+
+// Users is a row of table users.
+type Users struct {
+	// SQL: INT NOT NULL PRIMARY KEY AUTO_INCREMENT
+	ID int `+"`db:\"id\"`"+`
+	// SQL: VARCHAR(255)
+	Name string `+"`db:\"name\"`"+`
+	Token [16]byte `+"`db:\"token\" sql:\"BINARY(16) NOT NULL\"`"+`
+}
+`))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	if migration := Diff([]*model.Database{current}, desired); !migration.IsEmpty() {
+		t.Errorf("Diff() = %s, want empty migration", migration)
+	}
+}
+
+func TestLoadSchemaRejectsFieldsWithoutDefinition(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType string
+		want      string
+	}{
+		{name: "plain type", fieldType: "string", want: "go type string"},
+		{name: "array with length", fieldType: "[16]byte", want: "go type [16]byte"},
+		{name: "unsupported expression", fieldType: "map[string]int", want: "unsupported go type expression"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeGoSchema(t, "package models\n\ntype Users struct {\n\tValue "+test.fieldType+
+				" `db:\"value\"`\n}\n")
+
+			_, err := NewMigrator(mysql.NewParser(nil), nil).LoadSchema(path)
+			if !errors.Is(err, ErrNoColumnDefinition) {
+				t.Fatalf("LoadSchema() error = %v, want %v", err, ErrNoColumnDefinition)
+			}
+
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("LoadSchema() error = %v, want it to contain %q", err, test.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 var (
 	ErrMigrationNotFound = errors.New("migration not found")
@@ -9,15 +14,73 @@ var (
 )
 
 type Database struct {
-	Disabled           bool
-	TableNames         TableNames
-	Columns            []Column
-	FailedParseColumns []FailedParsedColumn
+	Disabled           bool                 `json:"disabled,omitempty"`
+	TableNames         TableNames           `json:"table_names"`
+	Columns            []Column             `json:"columns"`
+	Indexes            []Index              `json:"indexes,omitempty"`
+	ForeignKeys        []ForeignKey         `json:"foreign_keys,omitempty"`
+	FailedParseColumns []FailedParsedColumn `json:"failed_parse_columns,omitempty"`
+	// SourceFiles файлы миграций, из которых собрана таблица: CREATE TABLE и последующие ALTER TABLE по порядку.
+	SourceFiles []string `json:"source_files,omitempty"`
+	// Comment комментарий таблицы из COMMENT='...'.
+	Comment string `json:"comment,omitempty"`
+}
+
+// PrimaryKey возвращает колонки первичного ключа, объявленного в строке колонки или отдельной строкой PRIMARY KEY (...).
+func (d *Database) PrimaryKey() []string {
+	for _, index := range d.Indexes {
+		if index.IsPrimary {
+			return index.Columns
+		}
+	}
+
+	var primaryKey []string
+	for _, col := range d.Columns {
+		if col.IsPrimaryKey {
+			primaryKey = append(primaryKey, col.OriginalName)
+		}
+	}
+
+	return primaryKey
+}
+
+// ForeignKeyName имя i-го внешнего ключа в базе. Ключу без имени MySQL даёт имя <таблица>_ibfk_<номер>,
+// где номер - позиция среди ключей без имени.
+func (d *Database) ForeignKeyName(i int) string {
+	if name := d.ForeignKeys[i].Name; name != "" {
+		return name
+	}
+
+	number := 0
+	for _, foreignKey := range d.ForeignKeys[:i+1] {
+		if foreignKey.Name == "" {
+			number++
+		}
+	}
+
+	return d.TableNames.Original + "_ibfk_" + strconv.Itoa(number)
+}
+
+// IsIndexed сообщает, является ли колонка первой в каком-либо индексе, первичном ключе или уникальной.
+func (d *Database) IsIndexed(columnName string) bool {
+	for _, index := range d.Indexes {
+		if len(index.Columns) > 0 && index.Columns[0] == columnName {
+			return true
+		}
+	}
+
+	for _, col := range d.Columns {
+		if col.OriginalName == columnName {
+			return col.IsPrimaryKey || col.IsUnique
+		}
+	}
+
+	return false
 }
 
 func (d *Database) IsHaveTime() bool {
 	for _, col := range d.Columns {
-		if col.IsTime() {
+		if !col.IsDisable && col.IsTime() {
 			return true
 		}
 	}
@@ -26,18 +89,32 @@ func (d *Database) IsHaveTime() bool {
 }
 
 type TableNames struct {
-	CamelCase string
-	Original  string
+	CamelCase string `json:"camel_case"`
+	Original  string `json:"original"`
+	// Schema схема из CREATE TABLE schema.table, пуста, если имя не квалифицировано.
+	Schema string `json:"schema,omitempty"`
 }
 
 type Column struct {
-	OriginalName  string
-	CamelCaseName string
-	Type          string
-	DefaultValue  any
-	EnumValues    []string
-	IsNull        bool
-	IsDisable     bool
+	OriginalName  string `json:"original_name"`
+	CamelCaseName string `json:"camel_case_name"`
+	Type          string `json:"type"`
+	// SQLType исходный тип из миграции вместе с аргументами, например "VARCHAR(255)" или "INT UNSIGNED".
+	SQLType      string   `json:"sql_type,omitempty"`
+	DefaultValue any      `json:"default_value,omitempty"`
+	EnumValues   []string `json:"enum_values,omitempty"`
+	IsNull       bool     `json:"is_null"`
+	IsDisable    bool     `json:"is_disable,omitempty"`
+	// Ограничения, объявленные прямо в строке колонки.
+	IsPrimaryKey    bool `json:"is_primary_key,omitempty"`
+	IsAutoIncrement bool `json:"is_auto_increment,omitempty"`
+	IsUnique        bool `json:"is_unique,omitempty"`
+	// SourceFile файл миграции, в котором колонка объявлена последний раз, LineNumber строка в нём.
+	// Для колонки, которая пришла не из миграции, оба пусты.
+	SourceFile string `json:"source_file,omitempty"`
+	LineNumber int    `json:"line_number,omitempty"`
+	// Comment комментарий колонки из COMMENT '...'.
+	Comment string `json:"comment,omitempty"`
 }
 
 func (c *Column) IsTime() bool {
@@ -48,11 +125,73 @@ func (c *Column) IsEnum() bool {
 	return c.Type == "enum"
 }
 
+type Index struct {
+	Name      string   `json:"name,omitempty"`
+	Columns   []string `json:"columns"`
+	IsPrimary bool     `json:"is_primary,omitempty"`
+	IsUnique  bool     `json:"is_unique,omitempty"`
+}
+
+// DatabaseName имя индекса в базе: индексу без имени MySQL даёт имя его первой колонки.
+func (i *Index) DatabaseName() string {
+	if i.Name != "" || len(i.Columns) == 0 {
+		return i.Name
+	}
+
+	return i.Columns[0]
+}
+
+type ForeignKey struct {
+	Name              string   `json:"name,omitempty"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
 type FailedParsedColumn struct {
-	OriginalName  string
-	CamelCaseName string
-	LineNumber    int
-	Reason        error
+	OriginalName  string `json:"original_name"`
+	CamelCaseName string `json:"camel_case_name"`
+	LineNumber    int    `json:"line_number"`
+	Reason        error  `json:"-"`
+	// Snippet исходная строка миграции.
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// IsResolvable сообщает, что из строки удалось получить имя колонки, и ей можно назначить тип вручную.
+func (f *FailedParsedColumn) IsResolvable() bool {
+	return f.OriginalName != "" && f.OriginalName != "none"
+}
+
+// ResolveFailedColumn превращает неразобранную строку в колонку с заданным Go типом.
+// Колонка встаёт на место по номеру строки, чтобы порядок полей совпадал с миграцией.
+func (d *Database) ResolveFailedColumn(index int, goType string) Column {
+	failed := d.FailedParseColumns[index]
+	column := Column{
+		OriginalName:  failed.OriginalName,
+		CamelCaseName: failed.CamelCaseName,
+		Type:          goType,
+		IsNull:        !strings.Contains(strings.ToLower(failed.Snippet), "not null"),
+		LineNumber:    failed.LineNumber,
+	}
+
+	// Неразобранные строки бывают только в CREATE TABLE, первом файле таблицы.
+	if len(d.SourceFiles) > 0 {
+		column.SourceFile = d.SourceFiles[0]
+	}
+
+	position := len(d.Columns)
+	for i, col := range d.Columns {
+		if col.SourceFile == column.SourceFile && col.LineNumber > failed.LineNumber {
+			position = i
+
+			break
+		}
+	}
+
+	d.Columns = slices.Insert(d.Columns, position, column)
+	d.FailedParseColumns = slices.Delete(d.FailedParseColumns, index, index+1)
+
+	return column
 }
 
 // SupportedTypes содержит поддерживаемые типы данных и их синонимы - При парсинге приводить к нижнему регистру.
@@ -0,0 +1,95 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ToSnakeCase переводит CamelCase и camelCase в snake_case, аббревиатуры считаются одним словом: TestJSON -> test_json.
+func ToSnakeCase(name string) string {
+	runes := []rune(name)
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			isWordStart := i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+			if isWordStart {
+				builder.WriteRune('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// ToCamelCase переводит snake_case в CamelCase, уже CamelCase имена не меняются: test_text -> TestText.
+func ToCamelCase(name string) string {
+	words := strings.Split(name, "_")
+
+	var builder strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+
+		runes := []rune(word)
+		builder.WriteRune(unicode.ToUpper(runes[0]))
+		builder.WriteString(string(runes[1:]))
+	}
+
+	return builder.String()
+}
+
+// ToLowerCamelCase как ToCamelCase, но с маленькой первой буквой: test_text -> testText.
+func ToLowerCamelCase(name string) string {
+	camelCase := []rune(ToCamelCase(name))
+	if len(camelCase) == 0 {
+		return ""
+	}
+
+	camelCase[0] = unicode.ToLower(camelCase[0])
+
+	return string(camelCase)
+}
+
+// ApplyInitialisms заменяет слова CamelCase имени на аббревиатуры: UserId -> UserID при initialisms ["ID"].
+func ApplyInitialisms(name string, initialisms []string) string {
+	for _, initialism := range initialisms {
+		if initialism == "" {
+			continue
+		}
+
+		word := string(unicode.ToUpper([]rune(initialism)[0])) + strings.ToLower(initialism[1:])
+
+		var builder strings.Builder
+		for rest := name; ; {
+			index := strings.Index(rest, word)
+			if index < 0 {
+				builder.WriteString(rest)
+
+				break
+			}
+
+			end := index + len(word)
+			isWordEnd := end == len(rest) || !unicode.IsLower([]rune(rest[end:])[0])
+			builder.WriteString(rest[:index])
+			if isWordEnd {
+				builder.WriteString(strings.ToUpper(initialism))
+			} else {
+				builder.WriteString(word)
+			}
+
+			rest = rest[end:]
+		}
+
+		name = builder.String()
+	}
+
+	return name
+}
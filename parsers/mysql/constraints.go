@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+var (
+	rePrimaryKey = regexp.MustCompile("(?i)^(?:CONSTRAINT\\s+`?\\w*`?\\s*)?PRIMARY\\s+KEY\\s*\\(([^)]*)\\)")
+	reIndex      = regexp.MustCompile(
+		"(?i)^(?:CONSTRAINT\\s+`?\\w+`?\\s+)?(UNIQUE|FULLTEXT|SPATIAL)?\\s*(?:KEY|INDEX)\\b\\s*`?(\\w*)`?\\s*\\(([^)]*)\\)")
+	reUnique     = regexp.MustCompile("(?i)^(?:CONSTRAINT\\s+`?(\\w+)`?\\s+)?UNIQUE\\s*\\(([^)]*)\\)")
+	reForeignKey = regexp.MustCompile(
+		"(?i)^(?:CONSTRAINT\\s+`?(\\w+)`?\\s+)?FOREIGN\\s+KEY\\s*(?:`?\\w+`?\\s*)?\\(([^)]*)\\)\\s*REFERENCES\\s+`?(\\w+)`?\\s*\\(([^)]*)\\)")
+	reInlineReference = regexp.MustCompile("(?i)\\bREFERENCES\\s+`?(\\w+)`?\\s*\\(([^)]*)\\)")
+)
+
+// GetConstraints разбирает индексы и внешние ключи: отдельные строки PRIMARY KEY, KEY/INDEX, UNIQUE,
+// FOREIGN KEY ... REFERENCES, а также REFERENCES в строке колонки.
+func (p *Parser) GetConstraints(fileInfo []byte) ([]model.Index, []model.ForeignKey) {
+	p.logger.Debug("GetConstraints called")
+
+	var (
+		indexes     []model.Index
+		foreignKeys []model.ForeignKey
+	)
+
+	for line := range bytes.Lines(fileInfo) {
+		if p.isLineContainsCreate(line) {
+			continue
+		}
+
+		line = p.clearLine(line)
+
+		if index, ok := p.parseIndex(line); ok {
+			indexes = append(indexes, index)
+
+			continue
+		}
+
+		if matches := reForeignKey.FindSubmatch(line); len(matches) > 4 {
+			foreignKeys = append(foreignKeys, model.ForeignKey{
+				Name:              string(matches[1]),
+				Columns:           splitColumnList(matches[2]),
+				ReferencedTable:   string(matches[3]),
+				ReferencedColumns: splitColumnList(matches[4]),
+			})
+
+			continue
+		}
+
+		if matches := reInlineReference.FindSubmatch(line); len(matches) > 2 {
+			column, err := p.parseColumn(line)
+			if err != nil {
+				continue
+			}
+
+			foreignKeys = append(foreignKeys, model.ForeignKey{
+				Columns:           []string{column.OriginalName},
+				ReferencedTable:   string(matches[1]),
+				ReferencedColumns: splitColumnList(matches[2]),
+			})
+		}
+	}
+
+	p.logger.Debug("GetConstraints finished",
+		zap.Int("indexesCount", len(indexes)),
+		zap.Int("foreignKeysCount", len(foreignKeys)))
+
+	return indexes, foreignKeys
+}
+
+// isConstraintLine сообщает, что строка описывает индекс или ключ таблицы, а не колонку.
+func (p *Parser) isConstraintLine(line []byte) bool {
+	if _, ok := p.parseIndex(line); ok {
+		return true
+	}
+
+	return reForeignKey.Match(line) || bytes.HasPrefix(bytes.ToUpper(line), []byte("CONSTRAINT"))
+}
+
+func (p *Parser) parseIndex(line []byte) (model.Index, bool) {
+	if matches := rePrimaryKey.FindSubmatch(line); len(matches) > 1 {
+		return model.Index{Name: "PRIMARY", Columns: splitColumnList(matches[1]), IsPrimary: true, IsUnique: true}, true
+	}
+
+	if matches := reIndex.FindSubmatch(line); len(matches) > 3 {
+		return model.Index{
+			Name:     string(matches[2]),
+			Columns:  splitColumnList(matches[3]),
+			IsUnique: strings.EqualFold(string(matches[1]), "unique"),
+		}, true
+	}
+
+	if matches := reUnique.FindSubmatch(line); len(matches) > 2 {
+		return model.Index{Name: string(matches[1]), Columns: splitColumnList(matches[2]), IsUnique: true}, true
+	}
+
+	return model.Index{}, false
+}
+
+func splitColumnList(list []byte) []string {
+	parts := strings.Split(string(list), ",")
+
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		column := strings.Trim(strings.TrimSpace(part), "`\"")
+		if column != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
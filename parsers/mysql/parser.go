@@ -2,10 +2,13 @@ package mysql
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
@@ -14,73 +17,55 @@ import (
 )
 
 type Parser struct {
-	migrationPath string
-	logger        *zap.Logger
+	logger *zap.Logger
 }
 
-func NewParser(migrationPath string, logger *zap.Logger) *Parser {
+// NewParser создаёт парсер MySQL миграций. Миграции из директории или fs.FS читает generator.Parse.
+func NewParser(logger *zap.Logger) *Parser {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
-	return &Parser{migrationPath: migrationPath, logger: logger.Named("MySQL Parser: ")}
+	return &Parser{logger: logger.Named("MySQL Parser: ")}
 }
 
-func (p *Parser) GetDatabasesFromMigrations(migrationPath string) ([]*model.Database, error) {
-	p.logger.Info("Parse migrations", zap.String("migrationPath", migrationPath))
-	paths, err := p.GetPaths(migrationPath)
+// ParseMigration разбирает одну миграцию. path нужен только для SourceFiles и может быть пустым.
+func (p *Parser) ParseMigration(path string, fileInfo []byte) (*model.Database, error) {
+	TableName, err := p.GetTableName(fileInfo)
 	if err != nil {
-		p.logger.Debug("GetPaths error", zap.Error(err))
+		p.logger.Debug("GetTableName error", zap.Error(err))
 
 		return nil, err
 	}
+	p.logger.Debug("Parsed table name", zap.Any("TableName", TableName))
 
-	var databases []*model.Database
-	for _, path := range paths {
-		p.logger.Info("Processing migration file", zap.String("path", path))
-		var fileInfo []byte
-		fileInfo, err = os.ReadFile(path)
-		if err != nil {
-			p.logger.Debug("os.ReadFile error", zap.Error(err), zap.String("path", path))
-
-			return nil, err
-		}
-
-		TableName, err := p.GetTableName(fileInfo)
-		if err != nil {
-			p.logger.Debug("GetTableName error", zap.Error(err))
-
-			return nil, err
-		}
-		p.logger.Debug("Parsed table name", zap.Any("TableName", TableName))
-
-		columns, failedColumns, err := p.GetColumns(fileInfo)
-		if err != nil {
-			p.logger.Debug("GetColumns error", zap.Error(err))
+	columns, failedColumns, err := p.GetColumns(fileInfo)
+	if err != nil {
+		p.logger.Debug("GetColumns error", zap.Error(err))
 
-			return nil, err
-		}
-		p.logger.Debug("Parsed columns")
-
-		databases = append(databases, &model.Database{
-			TableNames: model.TableNames{
-				CamelCase: TableName.CamelCase,
-				Original:  TableName.Original,
-			},
-			Columns:            columns,
-			FailedParseColumns: failedColumns,
-		})
+		return nil, err
 	}
+	p.logger.Debug("Parsed columns")
 
-	if len(databases) == 0 {
-		p.logger.Debug("No databases found in migrations")
+	indexes, foreignKeys := p.GetConstraints(fileInfo)
 
-		return nil, model.ErrMigrationNotFound
+	var sourceFiles []string
+	if path != "" {
+		sourceFiles = []string{path}
+		for i := range columns {
+			columns[i].SourceFile = path
+		}
 	}
 
-	p.logger.Info("Successfully parsed databases", zap.Int("count", len(databases)))
-
-	return databases, nil
+	return &model.Database{
+		TableNames:         TableName,
+		Columns:            columns,
+		Indexes:            indexes,
+		ForeignKeys:        foreignKeys,
+		FailedParseColumns: failedColumns,
+		SourceFiles:        sourceFiles,
+		Comment:            p.GetTableComment(fileInfo),
+	}, nil
 }
 
 const (
@@ -92,7 +77,15 @@ const (
 var (
 	reGetColumns = regexp.MustCompile(`\b\w+\b`)
 	reGetEnums   = regexp.MustCompile(`\(([^)]*)\)`)
-	reGetDefault = regexp.MustCompile(`(?i)DEFAULT\s+(['"]?[\w\s]*['"]?)`)
+	reGetDefault = regexp.MustCompile(`(?i)DEFAULT\s+('[^']*'|"[^"]*"|[\w.()-]+)`)
+	reGetComment = regexp.MustCompile(`(?i)\bCOMMENT\s*=?\s*(?:'((?:[^'\\]|\\.|'')*)'|"((?:[^"\\]|\\.|"")*)")`)
+	reGetSQLType = regexp.MustCompile("(?i)^[`\"]?\\w+[`\"]?\\s+(\\w+(?:\\s*\\([^)]*\\))?(?:\\s+unsigned)?)")
+
+	reQuotedLiteral     = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"`)
+	reAttrNotNull       = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	reAttrPrimaryKey    = regexp.MustCompile(`(?i)\bPRIMARY\s+KEY\b`)
+	reAttrAutoIncrement = regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b`)
+	reAttrUnique        = regexp.MustCompile(`(?i)\bUNIQUE\b`)
 )
 
 func (p *Parser) isLineContainsCreate(line []byte) bool {
@@ -109,6 +102,18 @@ func (p *Parser) clearLine(line []byte) []byte {
 	return bytes.Trim(bytes.TrimSpace(line), ",")
 }
 
+// isStructuralLine пустые строки, скобки, опции таблицы после ")" и комментарии не являются колонками
+// и не считаются ошибкой разбора.
+func (p *Parser) isStructuralLine(line []byte) bool {
+	if bytes.HasPrefix(line, []byte(")")) {
+		return true
+	}
+
+	line = bytes.TrimSpace(bytes.Trim(line, "(;"))
+
+	return len(line) == 0 || bytes.HasPrefix(line, []byte("--")) || bytes.HasPrefix(line, []byte("#"))
+}
+
 func (p *Parser) GetColumns(fileInfo []byte) ([]model.Column, []model.FailedParsedColumn, error) {
 	p.logger.Debug("GetColumns called")
 
@@ -130,136 +135,228 @@ func (p *Parser) GetColumns(fileInfo []byte) ([]model.Column, []model.FailedPars
 
 		line = p.clearLine(line)
 
-		matches := reGetColumns.FindAllSubmatch(line, -1) // don't know how works this shit
-		if len(matches) < lenMatchesToParseNameAndType {
-			failedColumns = append(failedColumns, model.FailedParsedColumn{
-				OriginalName:  "none",
-				CamelCaseName: "none",
-				LineNumber:    currentLine,
-				Reason:        fmt.Errorf("line does not match expected column format"),
-			})
-			p.logger.Debug("Line does not match expected column format, skipping", zap.Int("lineNumber", currentLine))
+		if p.isStructuralLine(line) {
+			continue
+		}
+
+		if p.isConstraintLine(line) {
+			p.logger.Debug("Got constraint line, skipping", zap.Int("lineNumber", currentLine))
 
 			continue
 		}
 
-		originalName := string(matches[0][0])
-		camelCaseName := p.toCamelCase(originalName)
+		column, err := p.parseColumn(line)
+		if err != nil {
+			if column.OriginalName == "" {
+				column.OriginalName, column.CamelCaseName = "none", "none"
+			}
 
-		columnType, ok := model.ReverseSupportedTypes[string(bytes.ToLower(matches[1][0]))] // todo: rename
-		if !ok {
 			failedColumns = append(failedColumns, model.FailedParsedColumn{
-				OriginalName:  originalName,
-				CamelCaseName: camelCaseName,
+				OriginalName:  column.OriginalName,
+				CamelCaseName: column.CamelCaseName,
 				LineNumber:    currentLine,
-				Reason:        fmt.Errorf("unsupported column type: %s", string(matches[1][0])),
+				Reason:        err,
+				Snippet:       string(line),
 			})
-			p.logger.Debug("Unsupported column type found, skipping",
-				zap.String("type", string(matches[1][0])),
+			p.logger.Debug("Failed to parse column, skipping",
+				zap.Error(err),
 				zap.Int("lineNumber", currentLine))
 
 			continue
 		}
 
-		column := model.Column{
-			OriginalName:  originalName,
-			CamelCaseName: camelCaseName,
-			Type:          columnType,
-			IsNull:        !bytes.Contains(bytes.ToLower(line), []byte("not null")),
-		}
+		column.LineNumber = currentLine
+		columns = append(columns, column)
+	}
 
-		if strings.ToLower(column.Type) == "enum" {
-			enums := reGetEnums.FindSubmatch(line)
-			if len(enums) < minLenToEnums {
-				failedColumns = append(failedColumns, model.FailedParsedColumn{
-					OriginalName:  column.OriginalName,
-					CamelCaseName: column.CamelCaseName,
-					LineNumber:    currentLine,
-					Reason:        fmt.Errorf("enum type found but no values present"),
-				})
-				p.logger.Debug("Enum type found but no values present, skipping",
-					zap.String("column", column.OriginalName),
-					zap.Int("lineNumber", currentLine))
-
-				continue
-			}
+	p.logger.Debug("GetColumns finished", zap.Int("columnsCount", len(columns)))
 
-			enumValues := strings.Split(string(enums[1]), ",")
-			for _, enumValue := range enumValues {
-				trimmedEnumValue := strings.TrimPrefix(strings.Trim(enumValue, `'`), ` '`)
-				p.logger.Debug("Found enum", zap.String("enum", trimmedEnumValue))
+	return columns, failedColumns, nil
+}
 
-				column.EnumValues = append(column.EnumValues, trimmedEnumValue)
-			}
+// ParseColumnDefinition разбирает одно определение колонки, например "price DECIMAL(10, 2) NOT NULL DEFAULT 0".
+func (p *Parser) ParseColumnDefinition(definition string) (model.Column, error) {
+	return p.parseColumn(p.clearLine([]byte(definition)))
+}
+
+// parseColumn разбирает очищенную строку миграции. При ошибке в колонке заполнены имена, если их удалось получить.
+func (p *Parser) parseColumn(line []byte) (model.Column, error) {
+	// Текст комментария не должен влиять на разбор: COMMENT 'unique login' не делает колонку уникальной.
+	line, comment := cutComment(line)
+
+	matches := reGetColumns.FindAllSubmatch(line, -1) // don't know how works this shit
+	if len(matches) < lenMatchesToParseNameAndType {
+		return model.Column{}, fmt.Errorf("line does not match expected column format")
+	}
+
+	originalName := string(matches[0][0])
+	camelCaseName := p.toCamelCase(originalName)
+
+	columnType, ok := model.ReverseSupportedTypes[string(bytes.ToLower(matches[1][0]))] // todo: rename
+	if !ok {
+		return model.Column{OriginalName: originalName, CamelCaseName: camelCaseName},
+			fmt.Errorf("unsupported column type: %s", string(matches[1][0]))
+	}
+
+	// Ключевые слова ищутся целыми словами после имени колонки и вне строковых литералов:
+	// колонка unique_code или DEFAULT 'unique' не делают колонку уникальной.
+	attributes := reQuotedLiteral.ReplaceAll(line[reGetColumns.FindIndex(line)[1]:], []byte("''"))
+	isPrimaryKey := reAttrPrimaryKey.Match(attributes)
+	lowerLine := bytes.ToLower(line)
+	column := model.Column{
+		OriginalName:  originalName,
+		CamelCaseName: camelCaseName,
+		Type:          columnType,
+		// Колонка первичного ключа всегда NOT NULL, даже без явного ограничения.
+		IsNull:          !isPrimaryKey && !reAttrNotNull.Match(attributes),
+		IsPrimaryKey:    isPrimaryKey,
+		IsAutoIncrement: reAttrAutoIncrement.Match(attributes),
+		IsUnique:        reAttrUnique.Match(attributes),
+		Comment:         comment,
+	}
+
+	if sqlType := reGetSQLType.FindSubmatch(line); len(sqlType) > 1 {
+		column.SQLType = string(sqlType[1])
+	}
+
+	if strings.ToLower(column.Type) == "enum" {
+		enums := reGetEnums.FindSubmatch(line)
+		if len(enums) < minLenToEnums {
+			return column, fmt.Errorf("enum type found but no values present")
 		}
 
-		// Проверка на unsigned для целочисленных типов
-		if len(matches) > minimumLineLengthToHaveUint {
-			if bytes.Equal(bytes.ToLower(matches[2][0]), []byte(`unsigned`)) {
-				column.Type = "uint"
-			}
+		enumValues := strings.Split(string(enums[1]), ",")
+		for _, enumValue := range enumValues {
+			trimmedEnumValue := strings.TrimPrefix(strings.Trim(enumValue, `'`), ` '`)
+			p.logger.Debug("Found enum", zap.String("enum", trimmedEnumValue))
+
+			column.EnumValues = append(column.EnumValues, trimmedEnumValue)
 		}
+	}
 
-		if bytes.Contains(bytes.ToLower(line), []byte("default")) {
-			matchesDefaults := reGetDefault.FindSubmatch(line)
-			if len(matchesDefaults) > 1 {
-				p.logger.Debug("Found default value", zap.String("value", string(matchesDefaults[1])))
-				column.DefaultValue = strings.Trim(string(matchesDefaults[1]), `'`)
-			}
+	// Проверка на unsigned для целочисленных типов
+	if len(matches) > minimumLineLengthToHaveUint {
+		if bytes.Equal(bytes.ToLower(matches[2][0]), []byte(`unsigned`)) {
+			column.Type = "uint"
+		}
+	}
+
+	if bytes.Contains(lowerLine, []byte("default")) {
+		matchesDefaults := reGetDefault.FindSubmatch(line)
+		if len(matchesDefaults) > 1 {
+			p.logger.Debug("Found default value", zap.String("value", string(matchesDefaults[1])))
+			column.DefaultValue = strings.Trim(string(matchesDefaults[1]), `'"`)
 		}
+	}
 
-		columns = append(columns, column)
+	return column, nil
+}
+
+// cutComment убирает из строки COMMENT '...' и возвращает строку без него и текст комментария.
+func cutComment(line []byte) ([]byte, string) {
+	loc := reGetComment.FindSubmatchIndex(line)
+	if loc == nil {
+		return line, ""
 	}
 
-	p.logger.Debug("GetColumns finished", zap.Int("columnsCount", len(columns)))
+	// Первая группа - текст в одинарных кавычках, вторая - в двойных.
+	quote, start, end := "'", loc[2], loc[3]
+	if start < 0 {
+		quote, start, end = `"`, loc[4], loc[5]
+	}
 
-	return columns, failedColumns, nil
+	// Новый срез: line ссылается на содержимое файла, которое разбирается повторно.
+	rest := bytes.Join([][]byte{bytes.TrimSpace(line[:loc[0]]), bytes.TrimSpace(line[loc[1]:])}, []byte(" "))
+
+	return bytes.TrimSpace(rest), unquoteComment(string(line[start:end]), quote)
 }
 
-func (p *Parser) GetTableName(file []byte) (model.TableNames, error) {
-	p.logger.Debug("GetTableName called")
-	patternTableName := `(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`
-	reTableName, err := regexp.Compile(patternTableName)
-	if err != nil {
-		p.logger.Debug("regexp.Compile error", zap.Error(err))
+var commentEscapes = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\'`, "'", `\"`, `"`, `\\`, `\`)
+
+func unquoteComment(text, quote string) string {
+	return commentEscapes.Replace(strings.ReplaceAll(text, quote+quote, quote))
+}
 
-		return model.TableNames{}, fmt.Errorf("failed get structure name: %w", err)
+// GetTableComment комментарий таблицы из опций после закрывающей скобки: ) ENGINE=InnoDB COMMENT='...'.
+func (p *Parser) GetTableComment(file []byte) string {
+	closed := false
+	for line := range bytes.Lines(file) {
+		line = bytes.TrimSpace(line)
+		closed = closed || bytes.HasPrefix(line, []byte(")"))
+		if !closed {
+			continue
+		}
+
+		if _, comment := cutComment(line); comment != "" {
+			return comment
+		}
 	}
 
-	finded := reTableName.FindString(string(file))
+	return ""
+}
 
-	foundSlice := strings.Split(finded, " ")
+var reTableName = regexp.MustCompile("(?i)CREATE\\s+TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?" +
+	"(?:[`\"]?(\\w+)[`\"]?\\.)?[`\"]?(\\w+)")
 
-	tableName := strings.Join(foundSlice[len(foundSlice)-1:], "")
+// GetTableName имя таблицы из CREATE TABLE, в том числе квалифицированное схемой: shop.orders.
+func (p *Parser) GetTableName(file []byte) (model.TableNames, error) {
+	p.logger.Debug("GetTableName called")
 
-	p.logger.Debug("Extracted table name", zap.String("tableName", tableName))
+	matches := reTableName.FindSubmatch(file)
+	if matches == nil {
+		p.logger.Debug("CREATE TABLE not found")
+
+		return model.TableNames{}, fmt.Errorf("%w: CREATE TABLE not found", model.ErrInvalidMigration)
+	}
+
+	tableName := string(matches[2])
+
+	p.logger.Debug("Extracted table name", zap.String("tableName", tableName), zap.ByteString("schema", matches[1]))
 
 	return model.TableNames{
 		CamelCase: p.toCamelCase(tableName),
 		Original:  tableName,
+		Schema:    string(matches[1]),
 	}, nil
 }
 
-func (p *Parser) GetPaths(migration string) ([]string, error) {
-	p.logger.Debug("GetPaths called", zap.String("migration", migration))
-	pattern := fmt.Sprintf("%s/*.sql", migration)
+// IsDownMigration сообщает, что файл откатывает миграцию (<version>_<name>.down.sql). Такие файлы не входят в схему.
+func IsDownMigration(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".down.sql")
+}
 
-	paths, err := filepath.Glob(pattern)
-	if err != nil {
-		p.logger.Debug("filepath.Glob error", zap.Error(err))
+var reMigrationVersion = regexp.MustCompile(`^(\d+)_`)
 
-		return nil, fmt.Errorf("error finding migrations: %w", err)
-	}
+// SortMigrations упорядочивает миграции для применения: сначала файлы без версии по имени, затем файлы
+// golang-migrate ({version}_{title}.up.sql) по возрастанию версии.
+func SortMigrations(names []string) {
+	version := func(name string) (uint64, bool) {
+		matches := reMigrationVersion.FindStringSubmatch(path.Base(filepath.ToSlash(name)))
+		if matches == nil {
+			return 0, false
+		}
 
-	if len(paths) == 0 {
-		p.logger.Debug("No migration files found", zap.String("pattern", pattern))
+		number, err := strconv.ParseUint(matches[1], 10, 64)
 
-		return nil, model.ErrMigrationNotFound
+		return number, err == nil
 	}
 
-	p.logger.Debug("Found migration files", zap.Int("count", len(paths)))
+	slices.SortStableFunc(names, func(a, b string) int {
+		aVersion, aOk := version(a)
+		bVersion, bOk := version(b)
+		switch {
+		case aOk != bOk:
+			if aOk {
+				return 1
+			}
 
-	return paths, nil
+			return -1
+		case aVersion != bVersion:
+			return cmp.Compare(aVersion, bVersion)
+		default:
+			return strings.Compare(a, b)
+		}
+	})
 }
 
 func (p *Parser) toCamelCase(snakeCase string) string {
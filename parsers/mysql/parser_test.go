@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+func TestParseColumnDefinitionAttributes(t *testing.T) {
+	tests := []struct {
+		definition   string
+		isNull       bool
+		isPrimaryKey bool
+		isUnique     bool
+	}{
+		{definition: "id INT AUTO_INCREMENT PRIMARY KEY", isNull: false, isPrimaryKey: true},
+		{definition: "unique_code VARCHAR(32) NOT NULL", isNull: false},
+		{definition: "code VARCHAR(32) NOT NULL UNIQUE", isNull: false, isUnique: true},
+		{definition: "label VARCHAR(32) DEFAULT 'unique primary key'", isNull: true},
+		{definition: "login VARCHAR(32) COMMENT 'unique login'", isNull: true},
+		{definition: "not_null_flag TINYINT", isNull: true},
+	}
+
+	parser := NewParser(nil)
+	for _, tt := range tests {
+		column, err := parser.ParseColumnDefinition(tt.definition)
+		if err != nil {
+			t.Fatalf("ParseColumnDefinition(%q) error: %v", tt.definition, err)
+		}
+
+		if column.IsNull != tt.isNull || column.IsPrimaryKey != tt.isPrimaryKey || column.IsUnique != tt.isUnique {
+			t.Errorf("ParseColumnDefinition(%q) = null %v, primary key %v, unique %v, want %v, %v, %v",
+				tt.definition, column.IsNull, column.IsPrimaryKey, column.IsUnique, tt.isNull, tt.isPrimaryKey, tt.isUnique)
+		}
+	}
+}
+
+func TestApplyMigrationDropsUnnamedKeys(t *testing.T) {
+	migration := []byte("CREATE TABLE orders\n(\n" +
+		"    id INT PRIMARY KEY,\n" +
+		"    user_id INT NOT NULL,\n" +
+		"    KEY (user_id),\n" +
+		"    FOREIGN KEY (user_id) REFERENCES users (id)\n" +
+		");\n" +
+		"ALTER TABLE orders DROP FOREIGN KEY orders_ibfk_1, DROP INDEX user_id;\n")
+
+	databases, _, err := NewParser(nil).ApplyMigration(nil, "001_orders.sql", migration)
+	if err != nil {
+		t.Fatalf("ApplyMigration() error: %v", err)
+	}
+
+	if len(databases) != 1 {
+		t.Fatalf("ApplyMigration() returned %d tables, want 1", len(databases))
+	}
+
+	if orders := databases[0]; len(orders.Indexes) != 0 || len(orders.ForeignKeys) != 0 {
+		t.Errorf("orders indexes = %v, foreign keys = %v, want none", orders.Indexes, orders.ForeignKeys)
+	}
+}
+
+func TestApplyMigrationKeepsTablesOfDifferentSchemas(t *testing.T) {
+	parser := NewParser(nil)
+
+	var (
+		databases []*model.Database
+		err       error
+	)
+	for _, migration := range []string{
+		"CREATE TABLE shop.orders\n(\n    id INT PRIMARY KEY\n);\n",
+		"CREATE TABLE billing.orders\n(\n    id INT PRIMARY KEY\n);\n",
+		"ALTER TABLE shop.orders ADD COLUMN total INT NOT NULL;\n",
+	} {
+		databases, _, err = parser.ApplyMigration(databases, "migration.sql", []byte(migration))
+		if err != nil {
+			t.Fatalf("ApplyMigration(%q) error: %v", migration, err)
+		}
+	}
+
+	if len(databases) != 2 {
+		t.Fatalf("ApplyMigration() returned %d tables, want 2", len(databases))
+	}
+
+	for _, db := range databases {
+		wantColumns := 1
+		if db.TableNames.Schema == "shop" {
+			wantColumns = 2
+		}
+
+		if len(db.Columns) != wantColumns {
+			t.Errorf("%s.orders has %d columns, want %d", db.TableNames.Schema, len(db.Columns), wantColumns)
+		}
+	}
+
+	for _, statement := range []string{"ALTER TABLE orders DROP COLUMN id;\n", "DROP TABLE orders;\n"} {
+		if _, _, err = parser.ApplyMigration(databases, "migration.sql", []byte(statement)); !errors.Is(err, ErrAmbiguousTable) {
+			t.Errorf("ApplyMigration(%q) error = %v, want %v", statement, err, ErrAmbiguousTable)
+		}
+	}
+
+	databases, _, err = parser.ApplyMigration(databases, "migration.sql", []byte("DROP TABLE billing.orders;\n"))
+	if err != nil {
+		t.Fatalf("ApplyMigration() error: %v", err)
+	}
+
+	if len(databases) != 1 || databases[0].TableNames.Schema != "shop" {
+		t.Errorf("after DROP TABLE billing.orders tables = %v, want only shop.orders", databases)
+	}
+}
@@ -0,0 +1,449 @@
+package mysql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+var (
+	reStatementKind = regexp.MustCompile(`(?is)^(CREATE\s+TABLE|ALTER\s+TABLE|DROP\s+TABLE)\b`)
+	reAlterTable    = regexp.MustCompile("(?is)^ALTER\\s+TABLE\\s+(?:[`\"]?(\\w+)[`\"]?\\.)?[`\"]?(\\w+)[`\"]?\\s+(.*)$")
+	reDropTable     = regexp.MustCompile("(?is)^DROP\\s+TABLE\\s+(?:IF\\s+EXISTS\\s+)?(.*)$")
+
+	reAddColumn    = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(.*)$`)
+	reModifyColumn = regexp.MustCompile(`(?is)^MODIFY\s+(?:COLUMN\s+)?(.*)$`)
+	reChangeColumn = regexp.MustCompile("(?is)^CHANGE\\s+(?:COLUMN\\s+)?[`\"]?(\\w+)[`\"]?\\s+(.*)$")
+	reDropColumn   = regexp.MustCompile("(?is)^DROP\\s+(?:COLUMN\\s+)?[`\"]?(\\w+)[`\"]?$")
+	reRenameColumn = regexp.MustCompile("(?is)^RENAME\\s+COLUMN\\s+[`\"]?(\\w+)[`\"]?\\s+TO\\s+[`\"]?(\\w+)[`\"]?$")
+	reRenameTable  = regexp.MustCompile("(?is)^RENAME\\s+(?:TO\\s+|AS\\s+)?[`\"]?(\\w+)[`\"]?$")
+	reDropIndex    = regexp.MustCompile("(?is)^DROP\\s+(?:INDEX|KEY)\\s+[`\"]?(\\w+)[`\"]?$")
+	reDropPrimary  = regexp.MustCompile(`(?is)^DROP\s+PRIMARY\s+KEY$`)
+	reDropForeign  = regexp.MustCompile("(?is)^DROP\\s+FOREIGN\\s+KEY\\s+[`\"]?(\\w+)[`\"]?$")
+	rePosition     = regexp.MustCompile("(?is)\\s+(FIRST|AFTER\\s+[`\"]?(\\w+)[`\"]?)$")
+)
+
+// ErrAmbiguousTable ALTER или DROP TABLE без схемы, а таблица с таким именем есть в нескольких схемах.
+var ErrAmbiguousTable = errors.New("table name is ambiguous between schemas")
+
+// ApplyMigration применяет к схеме databases выражения одного файла миграции по порядку: CREATE TABLE добавляет
+// таблицу, ALTER TABLE меняет её, DROP TABLE удаляет. Остальные выражения пропускаются. Второе значение false,
+// если в файле нет ни одного выражения, меняющего таблицы.
+func (p *Parser) ApplyMigration(databases []*model.Database, path string, data []byte) ([]*model.Database, bool, error) {
+	applied := false
+	for _, statement := range splitStatements(data) {
+		text := stripComments(statement.text)
+		line := statement.line + bytes.Count(statement.text[:len(statement.text)-len(text)], []byte("\n"))
+		text = bytes.TrimSpace(text)
+		kind := reStatementKind.FindSubmatch(text)
+		if kind == nil {
+			continue
+		}
+
+		applied = true
+		switch strings.ToUpper(strings.Join(strings.Fields(string(kind[1])), " ")) {
+		case "CREATE TABLE":
+			database, err := p.ParseMigration(path, statement.text)
+			if err != nil {
+				return nil, false, err
+			}
+
+			shiftLines(database, statement.line-1)
+			databases = slices.DeleteFunc(databases, func(db *model.Database) bool {
+				return db.TableNames.Schema == database.TableNames.Schema &&
+					db.TableNames.Original == database.TableNames.Original
+			})
+			databases = append(databases, database)
+		case "DROP TABLE":
+			matches := reDropTable.FindSubmatch(text)
+			for _, name := range splitColumnList(bytes.TrimRight(matches[1], ";")) {
+				schema, table, _ := strings.Cut(name, ".")
+				if table == "" {
+					schema, table = "", schema
+				}
+
+				i, err := findTable(databases, strings.Trim(schema, "`\""), strings.Trim(table, "`\""))
+				if err != nil {
+					return nil, false, fmt.Errorf("%s: %w", path, err)
+				}
+
+				if i >= 0 {
+					databases = slices.Delete(databases, i, i+1)
+				}
+			}
+		case "ALTER TABLE":
+			if err := p.applyAlter(databases, path, line, bytes.TrimRight(text, ";")); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	return databases, applied, nil
+}
+
+// findTable индекс таблицы schema.name или -1. Имя без схемы подходит к таблице в любой схеме,
+// но только если такая таблица одна.
+func findTable(databases []*model.Database, schema, name string) (int, error) {
+	found := -1
+	for i, db := range databases {
+		if db.TableNames.Original != name || (schema != "" && db.TableNames.Schema != schema) {
+			continue
+		}
+
+		if found >= 0 {
+			return -1, fmt.Errorf("%w: %s", ErrAmbiguousTable, name)
+		}
+
+		found = i
+	}
+
+	return found, nil
+}
+
+// applyAlter применяет ALTER TABLE, который начинается на строке line файла path. Файл добавляется
+// в SourceFiles таблицы, а изменённые колонки получают его и свою строку.
+func (p *Parser) applyAlter(databases []*model.Database, path string, line int, statement []byte) error {
+	loc := reAlterTable.FindSubmatchIndex(statement)
+	if loc == nil {
+		p.logger.Warn("Unsupported ALTER TABLE, skipping", zap.String("path", path))
+
+		return nil
+	}
+
+	schema, table := statement[max(loc[2], 0):max(loc[3], 0)], statement[loc[4]:loc[5]]
+	i, err := findTable(databases, string(schema), string(table))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if i < 0 {
+		p.logger.Warn("ALTER TABLE of unknown table, skipping", zap.String("path", path), zap.ByteString("table", table))
+
+		return nil
+	}
+
+	database := databases[i]
+	if path != "" && !slices.Contains(database.SourceFiles, path) {
+		database.SourceFiles = append(database.SourceFiles, path)
+	}
+
+	offset := loc[6]
+	for _, clause := range splitTopLevel(statement[loc[6]:loc[7]], ',') {
+		trimmed := bytes.TrimLeft(clause, " \t\r\n")
+		clauseLine := line + bytes.Count(statement[:offset+len(clause)-len(trimmed)], []byte("\n"))
+		offset += len(clause) + 1
+
+		if !p.applyAlterClause(database, bytes.TrimSpace(clause), path, clauseLine) {
+			p.logger.Warn("Unsupported ALTER TABLE clause, skipping",
+				zap.String("path", path), zap.ByteString("clause", clause))
+		}
+	}
+
+	return nil
+}
+
+// applyAlterClause применяет одно действие ALTER TABLE со строки line файла path. false означает, что действие
+// не поддерживается.
+func (p *Parser) applyAlterClause(database *model.Database, clause []byte, path string, line int) bool {
+	upper := strings.ToUpper(string(clause))
+	switch {
+	case reDropPrimary.Match(clause):
+		setPrimaryKey(database, nil)
+	case reDropForeign.Match(clause):
+		dropForeignKey(database, string(reDropForeign.FindSubmatch(clause)[1]))
+	case reDropIndex.Match(clause):
+		dropIndex(database, string(reDropIndex.FindSubmatch(clause)[1]))
+	case reDropColumn.Match(clause):
+		name := string(reDropColumn.FindSubmatch(clause)[1])
+		database.Columns = slices.DeleteFunc(database.Columns, func(c model.Column) bool { return c.OriginalName == name })
+	case reRenameColumn.Match(clause):
+		matches := reRenameColumn.FindSubmatch(clause)
+		if i := columnIndex(database, string(matches[1])); i >= 0 {
+			database.Columns[i].OriginalName = string(matches[2])
+			database.Columns[i].CamelCaseName = p.toCamelCase(string(matches[2]))
+		}
+	case strings.HasPrefix(upper, "RENAME") && reRenameTable.Match(clause):
+		name := string(reRenameTable.FindSubmatch(clause)[1])
+		database.TableNames.Original, database.TableNames.CamelCase = name, p.toCamelCase(name)
+	case strings.HasPrefix(upper, "ADD"):
+		definition := reAddColumn.FindSubmatch(clause)[1]
+		if index, ok := p.parseIndex(definition); ok {
+			addIndex(database, index)
+
+			return true
+		}
+
+		if fk, ok := parseForeignKey(definition); ok {
+			database.ForeignKeys = append(database.ForeignKeys, fk)
+
+			return true
+		}
+
+		return p.putColumn(database, "", definition, path, line)
+	case reModifyColumn.Match(clause):
+		definition := reModifyColumn.FindSubmatch(clause)[1]
+		name := reGetColumns.Find(definition)
+
+		return p.putColumn(database, string(name), definition, path, line)
+	case reChangeColumn.Match(clause):
+		matches := reChangeColumn.FindSubmatch(clause)
+
+		return p.putColumn(database, string(matches[1]), matches[2], path, line)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// putColumn добавляет колонку (replace пуст) или заменяет колонку replace с учётом FIRST/AFTER. Ключи колонки
+// сохраняются: MODIFY в MySQL не удаляет индексы.
+func (p *Parser) putColumn(database *model.Database, replace string, definition []byte, path string, line int) bool {
+	position := rePosition.FindSubmatch(definition)
+	if position != nil {
+		definition = definition[:len(definition)-len(position[0])]
+	}
+
+	column, err := p.parseColumn(p.clearLine(definition))
+	if err != nil {
+		p.logger.Debug("Failed to parse column in ALTER TABLE", zap.Error(err))
+
+		return false
+	}
+
+	column.SourceFile, column.LineNumber = path, line
+
+	at := len(database.Columns)
+	if i := columnIndex(database, replace); i >= 0 {
+		old := database.Columns[i]
+		column.IsPrimaryKey = column.IsPrimaryKey || old.IsPrimaryKey
+		column.IsUnique = column.IsUnique || old.IsUnique
+		column.IsDisable = old.IsDisable
+		database.Columns = slices.Delete(database.Columns, i, i+1)
+		at = i
+	}
+
+	switch {
+	case position == nil:
+	case strings.EqualFold(string(position[1]), "FIRST"):
+		at = 0
+	default:
+		if i := columnIndex(database, string(position[2])); i >= 0 {
+			at = i + 1
+		}
+	}
+
+	database.Columns = slices.Insert(database.Columns, min(at, len(database.Columns)), column)
+
+	return true
+}
+
+func columnIndex(database *model.Database, name string) int {
+	if name == "" {
+		return -1
+	}
+
+	return slices.IndexFunc(database.Columns, func(c model.Column) bool { return c.OriginalName == name })
+}
+
+// addIndex добавляет индекс. Первичный ключ и уникальный индекс одной колонки с её именем хранятся в колонке,
+// как если бы были объявлены в её строке.
+func addIndex(database *model.Database, index model.Index) {
+	if index.IsPrimary {
+		setPrimaryKey(database, index.Columns)
+
+		return
+	}
+
+	if index.IsUnique && len(index.Columns) == 1 && (index.Name == "" || index.Name == index.Columns[0]) {
+		if i := columnIndex(database, index.Columns[0]); i >= 0 {
+			database.Columns[i].IsUnique = true
+
+			return
+		}
+	}
+
+	database.Indexes = append(database.Indexes, index)
+}
+
+func dropIndex(database *model.Database, name string) {
+	before := len(database.Indexes)
+	database.Indexes = slices.DeleteFunc(database.Indexes, func(index model.Index) bool {
+		return index.DatabaseName() == name
+	})
+	if len(database.Indexes) != before {
+		return
+	}
+
+	if i := columnIndex(database, name); i >= 0 {
+		database.Columns[i].IsUnique = false
+	}
+}
+
+// dropForeignKey удаляет внешний ключ по имени в базе, в том числе по имени, которое MySQL дал ключу без имени.
+func dropForeignKey(database *model.Database, name string) {
+	for i := range database.ForeignKeys {
+		if database.ForeignKeyName(i) == name {
+			database.ForeignKeys = slices.Delete(database.ForeignKeys, i, i+1)
+
+			return
+		}
+	}
+}
+
+// setPrimaryKey заменяет первичный ключ, пустой columns удаляет его.
+func setPrimaryKey(database *model.Database, columns []string) {
+	database.Indexes = slices.DeleteFunc(database.Indexes, func(index model.Index) bool { return index.IsPrimary })
+	for i := range database.Columns {
+		database.Columns[i].IsPrimaryKey = false
+	}
+
+	if len(columns) == 1 {
+		if i := columnIndex(database, columns[0]); i >= 0 {
+			database.Columns[i].IsPrimaryKey = true
+			database.Columns[i].IsNull = false
+
+			return
+		}
+	}
+
+	if len(columns) > 0 {
+		database.Indexes = append(database.Indexes, model.Index{Name: "PRIMARY", Columns: columns, IsPrimary: true, IsUnique: true})
+	}
+}
+
+func parseForeignKey(definition []byte) (model.ForeignKey, bool) {
+	matches := reForeignKey.FindSubmatch(definition)
+	if len(matches) < 5 {
+		return model.ForeignKey{}, false
+	}
+
+	return model.ForeignKey{
+		Name:              string(matches[1]),
+		Columns:           splitColumnList(matches[2]),
+		ReferencedTable:   string(matches[3]),
+		ReferencedColumns: splitColumnList(matches[4]),
+	}, true
+}
+
+// shiftLines переводит номера строк выражения в номера строк файла.
+func shiftLines(database *model.Database, offset int) {
+	for i := range database.Columns {
+		if database.Columns[i].LineNumber > 0 {
+			database.Columns[i].LineNumber += offset
+		}
+	}
+
+	for i := range database.FailedParseColumns {
+		database.FailedParseColumns[i].LineNumber += offset
+	}
+}
+
+type statement struct {
+	text []byte
+	// line строка файла, с которой начинается выражение.
+	line int
+}
+
+// splitStatements делит файл на выражения по ";" вне строк и комментариев.
+func splitStatements(data []byte) []statement {
+	var (
+		statements []statement
+		start      int
+		line       = 1
+		startLine  = 1
+		quote      byte
+		comment    bool
+	)
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\n':
+			line++
+			comment = false
+		case comment:
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#' || c == '-' && i+1 < len(data) && data[i+1] == '-':
+			comment = true
+		case c == ';':
+			statements = appendStatement(statements, data[start:i+1], startLine)
+			start, startLine = i+1, line
+		}
+	}
+
+	return appendStatement(statements, data[start:], startLine)
+}
+
+func appendStatement(statements []statement, text []byte, line int) []statement {
+	// Пустые строки перед выражением не сдвигают его начало.
+	trimmed := bytes.TrimLeft(text, " \t\r\n")
+	line += bytes.Count(text[:len(text)-len(trimmed)], []byte("\n"))
+	if len(bytes.TrimSpace(stripComments(trimmed))) == 0 {
+		return statements
+	}
+
+	return append(statements, statement{text: trimmed, line: line})
+}
+
+// stripComments убирает строки-комментарии "--" и "#" в начале выражения.
+func stripComments(text []byte) []byte {
+	for {
+		text = bytes.TrimLeft(text, " \t\r\n")
+		if !bytes.HasPrefix(text, []byte("--")) && !bytes.HasPrefix(text, []byte("#")) {
+			return text
+		}
+
+		end := bytes.IndexByte(text, '\n')
+		if end < 0 {
+			return nil
+		}
+
+		text = text[end+1:]
+	}
+}
+
+// splitTopLevel делит текст по sep вне скобок и кавычек.
+func splitTopLevel(text []byte, sep byte) [][]byte {
+	var (
+		parts [][]byte
+		start int
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, text[start:])
+}
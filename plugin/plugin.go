@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// ProtocolVersion версия формата Request и Response. Меняется при несовместимых изменениях.
+const ProtocolVersion = "1"
+
+var (
+	ErrPluginFailed    = errors.New("plugin failed")
+	ErrInvalidFileName = errors.New("plugin returned invalid file name")
+)
+
+// Plugin внешний генератор. Получает Request в stdin одним JSON документом и пишет Response в stdout.
+// Stderr плагина попадает в текст ошибки.
+type Plugin struct {
+	Name string `yaml:"name"`
+	// Command программа и аргументы, например ["./bin/gen-ts", "--strict"].
+	Command []string `yaml:"command"`
+	// Parameter произвольная строка настроек, передаётся плагину как есть.
+	Parameter string `yaml:"parameter,omitempty"`
+}
+
+type Request struct {
+	Version     string `json:"version"`
+	PackageName string `json:"package_name"`
+	// ImportPath Go путь импорта пакета моделей, если задан в настройках.
+	ImportPath string `json:"import_path,omitempty"`
+	Parameter  string `json:"parameter,omitempty"`
+	// Tables включенные таблицы с включенными и отключенными колонками (is_disable).
+	Tables []*model.Database `json:"tables"`
+}
+
+type Response struct {
+	Files []File `json:"files"`
+	// Error если не пуст, генерация прерывается с этим сообщением.
+	Error string `json:"error,omitempty"`
+}
+
+// File файл для записи. Name относительно корня вывода и не может выходить за его пределы.
+type File struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Run запускает плагин и возвращает файлы, которые он сгенерировал.
+func Run(ctx context.Context, plugin Plugin, request Request) ([]File, error) {
+	if len(plugin.Command) == 0 {
+		return nil, fmt.Errorf("%w: %s: command is empty", ErrPluginFailed, plugin.Name)
+	}
+
+	request.Version = ProtocolVersion
+	request.Parameter = plugin.Parameter
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Command[0], plugin.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w: %s", ErrPluginFailed, plugin.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	response := Response{}
+	if err = json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("%w: %s: invalid response: %w", ErrPluginFailed, plugin.Name, err)
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("%w: %s: %s", ErrPluginFailed, plugin.Name, response.Error)
+	}
+
+	for _, file := range response.Files {
+		if !filepath.IsLocal(file.Name) {
+			return nil, fmt.Errorf("%w: %s: %q", ErrInvalidFileName, plugin.Name, file.Name)
+		}
+	}
+
+	return response.Files, nil
+}
@@ -0,0 +1,50 @@
+package templater
+
+import (
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// HeaderLine первая строка каждого сгенерированного файла в формате, который распознают go vet, линтеры и GitHub.
+const HeaderLine = "// Code generated by go-generator-repository. DO NOT EDIT."
+
+var reGeneratedHeader = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// withHeader добавляет перед package заголовок с версией генератора и миграциями таблиц файла. Файлы, шаблон
+// которых уже пишет свой заголовок, не меняются.
+func (t *Templater) withHeader(code []byte, tables []TableData) []byte {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", code, parser.PackageClauseOnly)
+	if err != nil || reGeneratedHeader.Match(code[:fileSet.Position(file.Package).Offset]) {
+		return code
+	}
+
+	var sources []string
+	for _, table := range tables {
+		if table.Table == nil {
+			continue
+		}
+
+		for _, source := range table.Table.SourceFiles {
+			if !slices.Contains(sources, source) {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	slices.Sort(sources)
+
+	header := []string{HeaderLine}
+	if t.options.Version != "" {
+		header = append(header, "// version: "+t.options.Version)
+	}
+
+	for _, source := range sources {
+		header = append(header, "// source: "+source)
+	}
+
+	return append([]byte(strings.Join(header, "\n")+"\n\n"), code...)
+}
@@ -0,0 +1,200 @@
+package templater
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Области, которые переносятся из существующего файла при перегенерации:
+//
+//	// gen:keep begin User
+//	func (m *User) FullName() string { ... }
+//	// gen:keep end
+//
+// Встроенный шаблон модели содержит пустую область с именем модели в конце её кода. Области, которых нет в новом коде,
+// дописываются в конец файла. Код, который генератор не трогает совсем, можно держать в <table>_ext.go.
+const (
+	keepBeginMarker = "// gen:keep begin"
+	keepEndMarker   = "// gen:keep end"
+	// ExtFileSuffix суффикс файлов с ручным кодом рядом с моделями. Генератор такие файлы не создаёт и не удаляет.
+	ExtFileSuffix = "_ext.go"
+)
+
+var ErrUnbalancedKeepRegion = errors.New("unbalanced gen:keep region")
+
+type keptRegion struct {
+	name string
+	body string
+}
+
+// extractRegions возвращает области в порядке следования в файле.
+func extractRegions(code []byte) ([]keptRegion, error) {
+	var (
+		regions []keptRegion
+		current *keptRegion
+		body    strings.Builder
+	)
+	for i, line := range strings.SplitAfter(string(code), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, keepBeginMarker):
+			if current != nil {
+				return nil, fmt.Errorf("%w: nested begin on line %d", ErrUnbalancedKeepRegion, i+1)
+			}
+
+			current = &keptRegion{name: strings.TrimSpace(strings.TrimPrefix(trimmed, keepBeginMarker))}
+			body.Reset()
+		case trimmed == keepEndMarker:
+			if current == nil {
+				return nil, fmt.Errorf("%w: end without begin on line %d", ErrUnbalancedKeepRegion, i+1)
+			}
+
+			current.body = body.String()
+			regions = append(regions, *current)
+			current = nil
+		case current != nil:
+			body.WriteString(line)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: region %q is not closed", ErrUnbalancedKeepRegion, current.name)
+	}
+
+	return regions, nil
+}
+
+// mergeRegions подставляет содержимое kept в одноимённые области code. Области, которых в code нет,
+// дописываются в конец файла.
+func mergeRegions(code []byte, kept []keptRegion) []byte {
+	bodies := make(map[string]string, len(kept))
+	for _, region := range kept {
+		bodies[region.name] = region.body
+	}
+
+	var (
+		merged   strings.Builder
+		skipping bool
+	)
+	for _, line := range strings.SplitAfter(string(code), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, keepBeginMarker):
+			merged.WriteString(line)
+			name := strings.TrimSpace(strings.TrimPrefix(trimmed, keepBeginMarker))
+			if body, ok := bodies[name]; ok {
+				merged.WriteString(body)
+				delete(bodies, name)
+				skipping = true
+			}
+		case trimmed == keepEndMarker:
+			merged.WriteString(line)
+			skipping = false
+		case !skipping:
+			merged.WriteString(line)
+		}
+	}
+
+	for _, region := range kept {
+		if _, ok := bodies[region.name]; !ok || strings.TrimSpace(region.body) == "" {
+			continue
+		}
+
+		merged.WriteString("\n" + keepBeginMarker + " " + region.name + "\n" + region.body + keepEndMarker + "\n")
+	}
+
+	if formatted, err := format.Source([]byte(merged.String())); err == nil {
+		return formatted
+	}
+
+	return []byte(merged.String())
+}
+
+// keepRegions переносит области из файла name в директории savePath в новый код. tables таблицы, код которых
+// есть в файле, нужны, чтобы предупредить об областях, которые ссылаются на удалённые поля моделей.
+func (t *Templater) keepRegions(name string, code []byte, savePath string, tables []TableData) ([]byte, error) {
+	if savePath == "" {
+		return code, nil
+	}
+
+	existing, err := os.ReadFile(filepath.Join(savePath, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return code, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	kept, err := extractRegions(existing)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if len(kept) == 0 {
+		return code, nil
+	}
+
+	for i := range tables {
+		t.warnRemovedFields(name, existing, kept, &tables[i])
+	}
+
+	return mergeRegions(code, kept), nil
+}
+
+func (t *Templater) warnRemovedFields(name string, existing []byte, kept []keptRegion, data *TableData) {
+	current := make(map[string]bool, len(data.Fields))
+	for _, field := range data.Fields {
+		current[field.Name] = true
+	}
+
+	for _, field := range structFields(existing, data.ModelName) {
+		if current[field] {
+			continue
+		}
+
+		reference := regexp.MustCompile(`\.` + regexp.QuoteMeta(field) + `\b`)
+		for _, region := range kept {
+			if bytes.Contains([]byte(region.body), []byte(field)) && reference.MatchString(region.body) {
+				t.warnf("%s: kept region %q references removed field %s.%s", name, region.name, data.ModelName, field)
+			}
+		}
+	}
+}
+
+// structFields имена полей структуры typeName в коде. Неразбираемый код даёт пустой список.
+func structFields(code []byte, typeName string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", code, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var fields []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != typeName {
+			return true
+		}
+
+		if structType, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range structType.Fields.List {
+				for _, fieldName := range field.Names {
+					fields = append(fields, fieldName.Name)
+				}
+			}
+		}
+
+		return false
+	})
+
+	return fields
+}
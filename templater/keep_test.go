@@ -0,0 +1,71 @@
+package templater
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractRegionsUnbalanced(t *testing.T) {
+	tests := []string{
+		"// gen:keep begin User\n// gen:keep begin Order\n// gen:keep end\n",
+		"// gen:keep end\n",
+		"// gen:keep begin User\n",
+	}
+
+	for _, code := range tests {
+		if _, err := extractRegions([]byte(code)); !errors.Is(err, ErrUnbalancedKeepRegion) {
+			t.Errorf("extractRegions(%q) error = %v, want %v", code, err, ErrUnbalancedKeepRegion)
+		}
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	existing := `package models
+
+type User struct{}
+
+// gen:keep begin User
+func (m *User) FullName() string { return "" }
+// gen:keep end
+
+// gen:keep begin Removed
+func helper() {}
+// gen:keep end
+`
+
+	generated := `package models
+
+type User struct {
+	ID int
+}
+
+// gen:keep begin User
+// gen:keep end
+`
+
+	want := `package models
+
+type User struct {
+	ID int
+}
+
+// gen:keep begin User
+func (m *User) FullName() string { return "" }
+
+// gen:keep end
+
+// gen:keep begin Removed
+func helper() {}
+
+// gen:keep end
+`
+
+	kept, err := extractRegions([]byte(existing))
+	if err != nil {
+		t.Fatalf("extractRegions() error: %v", err)
+	}
+
+	if got := string(mergeRegions([]byte(generated), kept)); got != want {
+		t.Errorf("mergeRegions() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package templater
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+)
+
+// Раскладка пакетов (Options.Layout). Таблицы без схемы или из корня миграций остаются в корневом пакете.
+const (
+	// LayoutFlat все модели в одном пакете, поведение по умолчанию.
+	LayoutFlat = "flat"
+	// LayoutSchema пакет на схему: таблица shop.orders попадает в <out>/shop с пакетом shop.
+	LayoutSchema = "schema"
+	// LayoutDirectory пакет на поддиректорию миграций: миграция billing/001.sql попадает в <out>/billing.
+	LayoutDirectory = "directory"
+)
+
+// DefaultFileName шаблон имени файла модели по умолчанию.
+const DefaultFileName = "{{.Table.TableNames.Original}}_model.go"
+
+var (
+	ErrUnknownLayout    = errors.New("unknown layout")
+	ErrInvalidFileName  = errors.New("invalid file name")
+	ErrFileNameConflict = errors.New("file name conflict")
+)
+
+// modelPackage пакет вывода: поддиректория относительно savePath и её таблицы.
+type modelPackage struct {
+	dir        string
+	name       string
+	importPath string
+	tables     []*model.Database
+}
+
+func (t *Templater) validateLayout() error {
+	switch t.options.Layout {
+	case "", LayoutFlat, LayoutSchema, LayoutDirectory:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownLayout, t.options.Layout)
+	}
+}
+
+// packageDir поддиректория пакета таблицы, "" для корневого пакета.
+func (t *Templater) packageDir(database *model.Database) string {
+	switch t.options.Layout {
+	case LayoutSchema:
+		return database.TableNames.Schema
+	case LayoutDirectory:
+		// Пакет определяет файл с CREATE TABLE, ALTER TABLE из других директорий его не меняют.
+		if len(database.SourceFiles) == 0 {
+			return ""
+		}
+
+		if dir := path.Dir(filepath.ToSlash(database.SourceFiles[0])); dir != "." && filepath.IsLocal(dir) {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+func (t *Templater) modelPackage(dir, savePath string) modelPackage {
+	pkg := modelPackage{dir: dir, name: t.packageName(savePath), importPath: t.options.ImportPath}
+	if dir == "" {
+		return pkg
+	}
+
+	pkg.name = packageIdent(path.Base(dir))
+	if pkg.importPath != "" {
+		pkg.importPath = path.Join(pkg.importPath, dir)
+	}
+
+	return pkg
+}
+
+// groupPackages раскладывает включенные таблицы по пакетам. Корневой пакет идёт первым, остальные по имени.
+func (t *Templater) groupPackages(databases []*model.Database, savePath string) []modelPackage {
+	var packages []modelPackage
+	for _, db := range databases {
+		if db == nil || db.Disabled {
+			continue
+		}
+
+		dir := t.packageDir(db)
+		i := slices.IndexFunc(packages, func(pkg modelPackage) bool { return pkg.dir == dir })
+		if i < 0 {
+			packages = append(packages, t.modelPackage(dir, savePath))
+			i = len(packages) - 1
+		}
+
+		packages[i].tables = append(packages[i].tables, db)
+	}
+
+	slices.SortStableFunc(packages, func(a, b modelPackage) int { return strings.Compare(a.dir, b.dir) })
+
+	return packages
+}
+
+// packageIdent делает из имени директории или схемы имя пакета: Shop-Orders -> shop_orders.
+func packageIdent(name string) string {
+	ident := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return '_'
+		}
+	}, name)
+
+	if ident == "" || !unicode.IsLetter(rune(ident[0])) {
+		ident = "p" + ident
+	}
+
+	return ident
+}
+
+// parseFileName разбирает Options.FileName, пустой шаблон равен DefaultFileName.
+func (t *Templater) parseFileName() (*template.Template, error) {
+	pattern := t.options.FileName
+	if pattern == "" {
+		pattern = DefaultFileName
+	}
+
+	parsed, err := template.New("file_name").Funcs(FuncMap()).Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFileName, err)
+	}
+
+	return parsed, nil
+}
+
+// modelFileName имя файла модели относительно директории пакета.
+func modelFileName(pattern *template.Template, data TableData) (string, error) {
+	var buffer bytes.Buffer
+	if err := pattern.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFileName, err)
+	}
+
+	name := strings.TrimSpace(buffer.String())
+	if !filepath.IsLocal(name) || path.Ext(name) != ".go" || strings.HasSuffix(name, ExtFileSuffix) ||
+		strings.HasSuffix(name, "_test.go") {
+		return "", fmt.Errorf("%w: %q for table %s", ErrInvalidFileName, name, data.Table.TableNames.Original)
+	}
+
+	return name, nil
+}
+
+// mergeSources склеивает файлы одного пакета в один: заголовок и package берутся из первого файла,
+// импорты объединяются, остальной код идёт в исходном порядке.
+func mergeSources(sources [][]byte) ([]byte, error) {
+	if len(sources) == 1 {
+		return sources[0], nil
+	}
+
+	var (
+		header  []byte
+		imports []string
+		bodies  [][]byte
+	)
+	for i, source := range sources {
+		fileSet := token.NewFileSet()
+		file, err := parser.ParseFile(fileSet, "", source, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			header = source[:fileSet.Position(file.Name.End()).Offset]
+		}
+
+		for _, spec := range file.Imports {
+			importSpec := spec.Path.Value
+			if spec.Name != nil {
+				importSpec = spec.Name.Name + " " + importSpec
+			}
+
+			if !slices.Contains(imports, importSpec) {
+				imports = append(imports, importSpec)
+			}
+		}
+
+		bodyStart := fileSet.Position(file.Name.End()).Offset
+		if len(file.Decls) > 0 {
+			bodyStart = fileSet.Position(file.Decls[len(file.Decls)-1].End()).Offset
+		}
+
+		bodies = append(bodies, bytes.TrimSpace(source[bodyStart:]))
+	}
+
+	slices.Sort(imports)
+
+	var merged bytes.Buffer
+	merged.Write(header)
+	merged.WriteString("\n")
+	if len(imports) > 0 {
+		merged.WriteString("\nimport (\n\t" + strings.Join(imports, "\n\t") + "\n)\n")
+	}
+
+	for _, body := range bodies {
+		merged.WriteString("\n")
+		merged.Write(body)
+		merged.WriteString("\n")
+	}
+
+	return format.Source(merged.Bytes())
+}
@@ -0,0 +1,147 @@
+package templater
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var errNilOutput = errors.New("output is nil")
+
+// Output принимает сгенерированные файлы. name - путь файла относительно корня вывода.
+type Output interface {
+	WriteFile(name string, data []byte) error
+}
+
+const filePerm = 0o644
+
+// DirOutput пишет файлы в директорию. Каждый файл пишется во временный файл рядом и переименовывается,
+// поэтому при ошибке существующий файл остаётся прежним.
+type DirOutput struct {
+	Dir string
+}
+
+func (o DirOutput) WriteFile(name string, data []byte) (err error) {
+	path := filepath.Join(o.Dir, name)
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = temp.Close()
+			_ = os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err = temp.Write(data); err != nil {
+		return err
+	}
+
+	if err = temp.Sync(); err != nil {
+		return err
+	}
+
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(temp.Name(), filePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+// MemoryOutput собирает файлы в памяти, например для тестов или предпросмотра.
+type MemoryOutput struct {
+	mu    sync.Mutex
+	Files map[string][]byte
+}
+
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{Files: make(map[string][]byte)}
+}
+
+func (o *MemoryOutput) WriteFile(name string, data []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.Files[name] = append([]byte(nil), data...)
+
+	return nil
+}
+
+// WriterOutput пишет содержимое файлов в один поток друг за другом, например в stdout.
+type WriterOutput struct {
+	W io.Writer
+}
+
+func (o WriterOutput) WriteFile(_ string, data []byte) error {
+	_, err := o.W.Write(data)
+
+	return err
+}
+
+// ZipOutput пишет файлы в zip архив. Close дописывает оглавление архива, сам w не закрывается.
+type ZipOutput struct {
+	writer *zip.Writer
+}
+
+func NewZipOutput(w io.Writer) *ZipOutput {
+	return &ZipOutput{writer: zip.NewWriter(w)}
+}
+
+func (o *ZipOutput) WriteFile(name string, data []byte) error {
+	file, err := o.writer.CreateHeader(&zip.FileHeader{Name: filepath.ToSlash(name), Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+
+	return err
+}
+
+func (o *ZipOutput) Close() error {
+	return o.writer.Close()
+}
+
+// TarOutput пишет файлы в tar архив. Close дописывает конец архива, сам w не закрывается.
+type TarOutput struct {
+	writer *tar.Writer
+}
+
+func NewTarOutput(w io.Writer) *TarOutput {
+	return &TarOutput{writer: tar.NewWriter(w)}
+}
+
+func (o *TarOutput) WriteFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    filepath.ToSlash(name),
+		Mode:    filePerm,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := o.writer.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err := o.writer.Write(data)
+
+	return err
+}
+
+func (o *TarOutput) Close() error {
+	return o.writer.Close()
+}
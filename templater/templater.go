@@ -1,31 +1,132 @@
 package templater
 
 import (
-	"os"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 
 	"go.uber.org/zap"
 
 	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/naming"
 )
 
+var (
+	ErrUnknownGenerator = errors.New("unknown generator")
+	// ErrInvalidGeneratedCode шаблон дал код, который не разбирается как Go, например после неверного
+	// переопределения типа.
+	ErrInvalidGeneratedCode = errors.New("generated code is not valid Go")
+	ErrNameConflict         = errors.New("generated name conflict")
+)
+
+const (
+	GeneratorModels = "models"
+)
+
+// Generators все встроенные генераторы.
+var Generators = []string{GeneratorModels}
+
+const (
+	JSONTagNone     = "none"
+	JSONTagOriginal = "original"
+	JSONTagSnake    = "snake"
+	JSONTagCamel    = "camel"
+)
+
+// Options настройки генерации. Нулевое значение повторяет поведение по умолчанию.
+type Options struct {
+	// PackageName если пуст, берётся последний сегмент пути сохранения.
+	PackageName string
+	// JSONTags стиль имени в теге json, пустая строка равна JSONTagNone.
+	JSONTags      string
+	DisableDBTags bool
+	// Generators если пуст, запускаются все генераторы: встроенные и шаблоны из TemplateDir.
+	Generators []string
+	// TemplateDir директория пользовательских шаблонов, раскладка описана в templates.go.
+	TemplateDir string
+	// Layout раскладка моделей по пакетам, пустая строка равна LayoutFlat.
+	Layout string
+	// FileName шаблон имени файла модели с данными TableData, по умолчанию DefaultFileName. Модели с одинаковым
+	// именем файла пишутся в один файл, например "models.go" собирает все модели пакета вместе.
+	FileName string
+	// ImportPath Go путь импорта корневого пакета, доступен шаблонам и плагинам.
+	ImportPath string
+	// Version версия генератора для заголовка файлов, пустая строка не выводится.
+	Version string
+}
+
 type Templater struct {
-	logger *zap.Logger
+	options Options
+	logger  *zap.Logger
+
+	loadOnce  sync.Once
+	templates *templates
+	loadErr   error
+
+	// warnings предупреждения последнего вызова WriteModels.
+	warnings []string
 }
 
-func NewTemplater(logger *zap.Logger) *Templater {
+func NewTemplater(logger *zap.Logger, options Options) *Templater {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
-	return &Templater{logger: logger.Named("Templater: ")}
+	return &Templater{options: options, logger: logger.Named("Templater: ")}
+}
+
+// loadTemplates разбирает шаблоны при первом обращении, ошибка запоминается.
+func (t *Templater) loadTemplates() (*templates, error) {
+	t.loadOnce.Do(func() {
+		t.templates, t.loadErr = loadTemplates(t.options.TemplateDir)
+		if t.loadErr != nil {
+			t.logger.Error("Failed to load templates", zap.Error(t.loadErr))
+		}
+	})
+
+	return t.templates, t.loadErr
+}
+
+// Warnings предупреждения последнего вызова WriteModels, например о ручном коде в областях gen:keep,
+// который ссылается на удалённые поля.
+func (t *Templater) Warnings() []string {
+	return t.warnings
+}
+
+func (t *Templater) warnf(format string, args ...any) {
+	warning := fmt.Sprintf(format, args...)
+	t.logger.Warn(warning)
+	t.warnings = append(t.warnings, warning)
 }
 
 type Field struct {
 	Name string
 	Type string
 	Tags string
+	// Comment комментарий колонки из миграции, SQL её определение: "VARCHAR(255) NOT NULL UNIQUE".
+	Comment string
+	SQL     string
+	// Default значение по умолчанию из миграции как Go выражение: 34534, "text", TestTableTestEnumValue1,
+	// time.Now(). Пусто, если значения нет или его не удалось привести к типу поля.
+	Default string
+	// Column колонка, из которой получено поле, для проверок в шаблонах: {{if isNullable .Column}}.
+	Column model.Column
+}
+
+// ScanNull сообщает, что колонка допускает NULL, а тип поля его не принимает: Scan такого поля читает значение
+// через sql.Null и записывает в поле нулевое значение вместо NULL.
+func (f Field) ScanNull() bool {
+	return f.Column.IsNull && !strings.HasPrefix(f.Type, "*") && !strings.HasPrefix(f.Type, "sql.Null") &&
+		f.Type != "[]byte" && f.Type != "any"
 }
 
 type CustomType struct {
@@ -49,6 +150,12 @@ func (t *Templater) parseColumnsToFields(camelCasedDBName string, columns []mode
 	customTypes := make([]CustomType, 0, cap(columns))
 
 	for _, column := range columns {
+		if column.IsDisable {
+			t.logger.Debug("Column is disabled, skipping", zap.String("column", column.OriginalName))
+
+			continue
+		}
+
 		if column.IsEnum() {
 			t.logger.Debug("Column is enum type", zap.String("column", column.OriginalName))
 
@@ -62,9 +169,11 @@ func (t *Templater) parseColumnsToFields(camelCasedDBName string, columns []mode
 		}
 
 		field := Field{
-			Name: column.CamelCaseName,
-			Type: column.Type,
-			Tags: t.getTags(column),
+			Name:    column.CamelCaseName,
+			Type:    column.Type,
+			Tags:    t.getTags(column),
+			Comment: column.Comment,
+			Column:  column,
 		}
 		fields = append(fields, field)
 	}
@@ -72,17 +181,294 @@ func (t *Templater) parseColumnsToFields(camelCasedDBName string, columns []mode
 	return fields, customTypes
 }
 
+// knownPackages пакеты, которые подключаются автоматически, если тип поля на них ссылается (в том числе после
+// переопределения типа пользователем). Для остальных пакетов импорт нужно добавить вручную.
+var knownPackages = map[string]string{
+	"time":    "time",
+	"sql":     "database/sql",
+	"json":    "encoding/json",
+	"big":     "math/big",
+	"netip":   "net/netip",
+	"uuid":    "github.com/google/uuid",
+	"decimal": "github.com/shopspring/decimal",
+}
+
+var reTypePackage = regexp.MustCompile(`\b([a-z]\w*)\.[A-Z]`)
+
+func (t *Templater) getImports(fields []Field) []string {
+	var imports []string
+	for _, field := range fields {
+		for _, matches := range reTypePackage.FindAllStringSubmatch(field.Type, -1) {
+			importPath, ok := knownPackages[matches[1]]
+			if !ok {
+				t.logger.Warn("Unknown package in field type, add the import manually", zap.String("type", field.Type))
+
+				continue
+			}
+
+			if !slices.Contains(imports, importPath) {
+				imports = append(imports, importPath)
+			}
+		}
+	}
+
+	slices.Sort(imports)
+
+	return imports
+}
+
 func (t *Templater) getTags(column model.Column) string {
-	return `db:"` + column.OriginalName + `"`
+	var tags []string
+	if !t.options.DisableDBTags {
+		tags = append(tags, `db:"`+column.OriginalName+`"`)
+	}
+
+	switch t.options.JSONTags {
+	case JSONTagOriginal:
+		tags = append(tags, `json:"`+column.OriginalName+`"`)
+	case JSONTagSnake:
+		tags = append(tags, `json:"`+naming.ToSnakeCase(column.OriginalName)+`"`)
+	case JSONTagCamel:
+		tags = append(tags, `json:"`+naming.ToLowerCamelCase(column.OriginalName)+`"`)
+	}
+
+	return strings.Join(tags, " ")
 }
 
-func (t *Templater) SaveModels(databases []*model.Database, savePath string) error {
-	for _, db := range databases {
-		if db == nil || db.Disabled {
+var reSQLKeyword = regexp.MustCompile(`^[A-Z_]+(\(\))?$|^-?[0-9.]+$`)
+
+// sqlDefinition восстанавливает определение колонки для комментария: тип и ограничения из строки колонки.
+func sqlDefinition(database *model.Database, column model.Column) string {
+	definition := []string{column.SQLType}
+	if column.SQLType == "" {
+		definition[0] = strings.ToUpper(column.Type)
+	}
+
+	if !column.IsNull {
+		definition = append(definition, "NOT NULL")
+	}
+
+	if column.IsPrimaryKey {
+		definition = append(definition, "PRIMARY KEY")
+	}
+
+	if column.IsAutoIncrement {
+		definition = append(definition, "AUTO_INCREMENT")
+	}
+
+	if column.IsUnique {
+		definition = append(definition, "UNIQUE")
+	}
+
+	if column.DefaultValue != nil {
+		value := fmt.Sprint(column.DefaultValue)
+		if !reSQLKeyword.MatchString(value) {
+			value = "'" + value + "'"
+		}
+
+		definition = append(definition, "DEFAULT "+value)
+	}
+
+	for _, foreignKey := range database.ForeignKeys {
+		if len(foreignKey.Columns) == 1 && foreignKey.Columns[0] == column.OriginalName {
+			definition = append(definition, fmt.Sprintf("REFERENCES %s(%s)",
+				foreignKey.ReferencedTable, strings.Join(foreignKey.ReferencedColumns, ", ")))
+		}
+	}
+
+	return strings.Join(definition, " ")
+}
+
+var reCurrentTimestamp = regexp.MustCompile(`(?i)^(CURRENT_TIMESTAMP|NOW|LOCALTIMESTAMP|LOCALTIME)(\(\d*\))?$`)
+
+// defaultLiteral приводит DEFAULT колонки к Go литералу типа поля.
+func (t *Templater) defaultLiteral(field Field, customTypes []CustomType) string {
+	if field.Column.DefaultValue == nil {
+		return ""
+	}
+
+	value := fmt.Sprint(field.Column.DefaultValue)
+	if strings.EqualFold(value, "null") {
+		return ""
+	}
+
+	for _, customType := range customTypes {
+		if customType.Name != field.Type {
 			continue
 		}
 
-		if err := t.saveModel(db, savePath); err != nil {
+		normalized := t.normalizeEnumValues([]string{value})
+		if len(normalized) == 1 && slices.Contains(customType.Values, normalized[0]) {
+			return customType.Name + normalized[0]
+		}
+	}
+
+	switch {
+	case field.Type == "string":
+		return strconv.Quote(value)
+	case field.Type == "[]byte":
+		return "[]byte(" + strconv.Quote(value) + ")"
+	case field.Type == "bool":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(parsed)
+		}
+	case field.Type == "time.Time":
+		if reCurrentTimestamp.MatchString(value) {
+			return "time.Now()"
+		}
+	case strings.HasPrefix(field.Type, "float"):
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	case reNumericType.MatchString(field.Type) && !strings.HasPrefix(field.Type, "*"):
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return value
+		}
+	}
+
+	t.logger.Debug("Default value is not converted to the field type",
+		zap.String("field", field.Name), zap.String("type", field.Type), zap.String("default", value))
+
+	return ""
+}
+
+// SaveModels пишет модели в директорию savePath, см. WriteModels.
+func (t *Templater) SaveModels(databases []*model.Database, savePath string) error {
+	return t.WriteModels(databases, DirOutput{Dir: savePath}, savePath)
+}
+
+// WriteModels генерирует файлы всех включенных генераторов и пишет их в output. Сначала генерируются все файлы,
+// поэтому при ошибке генерации в output ничего не записывается. savePath нужен для имени пакета по умолчанию,
+// кроме того, из файлов в savePath переносятся области gen:keep (см. keep.go).
+func (t *Templater) WriteModels(databases []*model.Database, output Output, savePath string) error {
+	if output == nil {
+		return errNilOutput
+	}
+
+	t.warnings = nil
+
+	loaded, err := t.loadTemplates()
+	if err != nil {
+		return err
+	}
+
+	if err = t.validateGenerators(loaded); err != nil {
+		return err
+	}
+
+	if err = t.validateLayout(); err != nil {
+		return err
+	}
+
+	fileName, err := t.parseFileName()
+	if err != nil {
+		return err
+	}
+
+	// file собирается из кода одной или нескольких таблиц: модели с одинаковым именем файла склеиваются.
+	type file struct {
+		name      string
+		generator string
+		sources   [][]byte
+		tables    []TableData
+	}
+
+	var files []*file
+	add := func(name, generator string, code []byte, tables ...TableData) error {
+		i := slices.IndexFunc(files, func(f *file) bool { return f.name == name })
+		if i < 0 {
+			files = append(files, &file{name: name, generator: generator})
+			i = len(files) - 1
+		}
+
+		if files[i].generator != generator {
+			return fmt.Errorf("%w: %s is generated by %s and %s", ErrFileNameConflict, name, files[i].generator, generator)
+		}
+
+		files[i].sources = append(files[i].sources, code)
+		files[i].tables = append(files[i].tables, tables...)
+
+		return nil
+	}
+
+	for _, pkg := range t.groupPackages(databases, savePath) {
+		schema := SchemaData{PackageName: pkg.name, ImportPath: pkg.importPath}
+		declared := make(map[string]string)
+		for _, db := range pkg.tables {
+			t.logger.Info("Start creating model...", zap.String("database", db.TableNames.Original))
+
+			data := t.tableData(db, pkg)
+			schema.Tables = append(schema.Tables, data)
+
+			if t.isEnabled(GeneratorModels) {
+				if err = declareNames(declared, data, loaded.builtinModel); err != nil {
+					return err
+				}
+
+				name, err := modelFileName(fileName, data)
+				if err != nil {
+					return err
+				}
+
+				code, err := t.execute(loaded.model, data)
+				if err != nil {
+					return fmt.Errorf("failed to render model %s: %w", db.TableNames.Original, err)
+				}
+
+				if err = add(path.Join(pkg.dir, name), GeneratorModels, code, data); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(loaded.table)) {
+				if !t.isEnabled(name) {
+					continue
+				}
+
+				code, err := t.execute(loaded.table[name], data)
+				if err != nil {
+					return fmt.Errorf("failed to render %s for %s: %w", name, db.TableNames.Original, err)
+				}
+
+				err = add(path.Join(pkg.dir, db.TableNames.Original+"_"+name+".go"), name, code, data)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(loaded.schema)) {
+			if !t.isEnabled(name) {
+				continue
+			}
+
+			code, err := t.execute(loaded.schema[name], schema)
+			if err != nil {
+				return fmt.Errorf("failed to render %s: %w", name, err)
+			}
+
+			if err = add(path.Join(pkg.dir, name+".go"), name, code, schema.Tables...); err != nil {
+				return err
+			}
+		}
+	}
+
+	rendered := make([][]byte, len(files))
+	for i, f := range files {
+		code, err := mergeSources(f.sources)
+		if err != nil {
+			return fmt.Errorf("failed to merge %s: %w", f.name, err)
+		}
+
+		if rendered[i], err = t.keepRegions(f.name, t.withHeader(code, f.tables), savePath, f.tables); err != nil {
+			return err
+		}
+	}
+
+	for i, f := range files {
+		if err = output.WriteFile(f.name, rendered[i]); err != nil {
+			t.logger.Error("Failed to write file", zap.String("file", f.name), zap.Error(err))
+
 			return err
 		}
 	}
@@ -90,61 +476,178 @@ func (t *Templater) SaveModels(databases []*model.Database, savePath string) err
 	return nil
 }
 
-func (t *Templater) saveModel(database *model.Database, savePath string) error {
-	t.logger.Info("Start creating model...", zap.String("database", database.TableNames.Original))
+func (t *Templater) validateGenerators(loaded *templates) error {
+	for _, generator := range t.options.Generators {
+		_, isTable := loaded.table[generator]
+		_, isSchema := loaded.schema[generator]
+		if !slices.Contains(Generators, generator) && !isTable && !isSchema {
+			return fmt.Errorf("%w: %s", ErrUnknownGenerator, generator)
+		}
+	}
+
+	return nil
+}
+
+func (t *Templater) isEnabled(generator string) bool {
+	return len(t.options.Generators) == 0 || slices.Contains(t.options.Generators, generator)
+}
+
+func (t *Templater) packageName(savePath string) string {
+	if t.options.PackageName != "" {
+		return t.options.PackageName
+	}
+
+	return strings.Split(savePath, "/")[len(strings.Split(savePath, "/"))-1]
+}
+
+func (t *Templater) tableData(database *model.Database, pkg modelPackage) TableData {
 	fields, customTypes := t.parseColumnsToFields(database.TableNames.CamelCase, database.Columns)
+	for i := range fields {
+		fields[i].SQL = sqlDefinition(database, fields[i].Column)
+		fields[i].Default = t.defaultLiteral(fields[i], customTypes)
+	}
+
+	columns := make([]model.Column, 0, len(database.Columns))
+	for _, column := range database.Columns {
+		if !column.IsDisable {
+			columns = append(columns, column)
+		}
+	}
 
-	packageName := strings.Split(savePath, "/")[len(strings.Split(savePath, "/"))-1]
+	return TableData{
+		PackageName: pkg.name,
+		ImportPath:  pkg.importPath,
+		ModelName:   database.TableNames.CamelCase,
+		Table:       database,
+		Columns:     columns,
+		Fields:      fields,
+		PrimaryKey:  database.PrimaryKey(),
+		Indexes:     database.Indexes,
+		ForeignKeys: database.ForeignKeys,
+		CustomTypes: customTypes,
+		Imports:     t.getImports(fields),
+	}
+}
 
-	data := struct {
-		PackageName    string
-		ModelName      string
-		Fields         []Field
-		HasTimePackage bool
-		CustomTypes    []CustomType
-	}{
-		PackageName:    packageName,
-		ModelName:      database.TableNames.CamelCase,
-		Fields:         fields,
-		HasTimePackage: database.IsHaveTime(),
-		CustomTypes:    customTypes,
+// declareNames запоминает в declared имена пакета, которые объявляет модель data встроенного шаблона, и
+// возвращает ErrNameConflict, если имя уже объявлено моделью другой таблицы: например, тип UserColumn таблицы
+// user и модель таблицы user_column. Имена пользовательского шаблона модели неизвестны и не проверяются.
+func declareNames(declared map[string]string, data TableData, builtin bool) error {
+	if !builtin {
+		return nil
 	}
 
-	file, err := os.Create(savePath + "/" + database.TableNames.Original + "_model.go")
+	modelName := data.ModelName
+	names := []string{
+		modelName, "New" + modelName, modelName + "Column", modelName + "Columns",
+		"Scan" + modelName + "Row", "Scan" + modelName + "Rows",
+	}
+	for _, customType := range data.CustomTypes {
+		names = append(names, customType.Name)
+		for _, value := range customType.Values {
+			names = append(names, customType.Name+value)
+		}
+	}
+
+	table := data.Table.TableNames.Original
+	for _, name := range names {
+		if other, ok := declared[name]; ok && other != table {
+			return fmt.Errorf("%w: %s is declared for tables %s and %s, rename one of them (naming.tables in gen.yaml)",
+				ErrNameConflict, name, other, table)
+		}
+
+		declared[name] = table
+	}
+
+	return nil
+}
+
+// Render возвращает код модели таблицы так, как его запишет WriteModels: с заголовком и областями gen:keep из
+// файла в savePath. Для предпросмотра код, который не удалось отформатировать, возвращается как есть, чтобы
+// ошибку (например, от неверного переопределения типа) было видно.
+func (t *Templater) Render(database *model.Database, savePath string) ([]byte, error) {
+	loaded, err := t.loadTemplates()
 	if err != nil {
-		t.logger.Error("Failed to create file", zap.Error(err))
+		return nil, err
+	}
 
-		return err
+	fileName, err := t.parseFileName()
+	if err != nil {
+		return nil, err
 	}
 
-	templ, err := template.New(database.TableNames.CamelCase).Parse(templateText)
+	pkg := t.modelPackage(t.packageDir(database), savePath)
+	data := t.tableData(database, pkg)
+
+	name, err := modelFileName(fileName, data)
 	if err != nil {
-		t.logger.Error("Failed to parse template", zap.Error(err))
+		return nil, err
+	}
 
-		return err
+	code, err := t.execute(loaded.model, data)
+	if errors.Is(err, ErrInvalidGeneratedCode) {
+		var buffer bytes.Buffer
+		if err = loaded.model.Execute(&buffer, data); err != nil {
+			return nil, err
+		}
+
+		return t.withHeader(buffer.Bytes(), []TableData{data}), nil
 	}
 
-	err = templ.Execute(file, data)
 	if err != nil {
-		t.logger.Error("Failed to execute template", zap.Error(err))
+		return nil, err
+	}
 
-		return err
+	return t.keepRegions(path.Join(pkg.dir, name), t.withHeader(code, []TableData{data}), savePath, []TableData{data})
+}
+
+// execute выполняет шаблон и форматирует код. Код, который не форматируется, не является Go и не записывается.
+func (t *Templater) execute(templ *template.Template, data any) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := templ.Execute(&buffer, data)
+	if err != nil {
+		t.logger.Error("Failed to execute template", zap.String("template", templ.Name()), zap.Error(err))
+
+		return nil, err
 	}
 
-	return nil
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		t.logger.Debug("Failed to format generated code", zap.String("template", templ.Name()), zap.Error(err))
+
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGeneratedCode, err)
+	}
+
+	return code, nil
 }
 
-const templateText = `package {{.PackageName}} 
-{{if .HasTimePackage}}
-import "time"
-{{end}} 
+const templateText = `package {{.PackageName}}
+
+import (
+    "database/sql"
+{{- range .Imports}}{{if ne . "database/sql"}}
+    "{{.}}"
+{{- end}}{{end}}
+)
+
+// {{.ModelName}} is a row of table {{.Table.TableNames.Original}}.
+{{- if .Table.Comment}}
+//
+{{comment .Table.Comment}}
+{{- end}}
 type {{.ModelName}} struct {
 {{- range .Fields}}
-    {{.Name}} {{.Type}} ` + "`{{.Tags}}`" + `
+{{- if .Comment}}
+    {{comment .Comment}}
+    //
+{{- end}}
+    // SQL: {{.SQL}}
+    {{.Name}} {{.Type}}{{if .Tags}} ` + "`{{.Tags}}`" + `{{end}}
 {{- end}}
 }
 
 {{- range .CustomTypes}}
+
 type {{.Name}} {{.ParentType}}
 
 const (
@@ -154,4 +657,106 @@ const (
 {{- end}}
 )
 {{end}}
+// New{{.ModelName}} returns {{.ModelName}} with the column defaults from the migration.
+func New{{.ModelName}}() *{{.ModelName}} {
+    return &{{.ModelName}}{
+{{- range .Fields}}{{if .Default}}
+        {{.Name}}: {{.Default}},
+{{- end}}{{end}}
+    }
+}
+
+// {{.ModelName}}Column name of a {{.Table.TableNames.Original}} column.
+type {{.ModelName}}Column string
+
+// {{.ModelName}}Columns names of {{.Table.TableNames.Original}} columns for building queries.
+var {{.ModelName}}Columns = struct {
+{{- range .Fields}}
+    {{.Name}} {{$.ModelName}}Column
+{{- end}}
+}{
+{{- range .Fields}}
+    {{.Name}}: {{quote .Column.OriginalName}},
+{{- end}}
+}
+{{if not (.HasField "TableName")}}
+// TableName returns the name of the table in the database.
+func ({{.ModelName}}) TableName() string {
+    return {{quote .QualifiedName}}
+}
+{{end}}
+{{- if not (.HasField "Columns")}}
+// Columns returns the column names in the migration order.
+func ({{.ModelName}}) Columns() []string {
+    return []string{ {{- range .Fields}}{{quote .Column.OriginalName}}, {{end -}} }
+}
+{{end}}
+{{- if not (.HasField "PrimaryKey")}}
+// PrimaryKey returns the primary key column names, nil if the table has none.
+func ({{.ModelName}}) PrimaryKey() []string {
+{{- if .PrimaryKey}}
+    return []string{ {{- range .PrimaryKey}}{{quote .}}, {{end -}} }
+{{- else}}
+    return nil
+{{- end}}
+}
+{{end}}
+{{- if not (.HasField "Values")}}
+// Values returns the field values in Columns() order, e.g. for INSERT arguments.
+func (m *{{.ModelName}}) Values() []any {
+    return []any{ {{- range .Fields}}m.{{.Name}}, {{end -}} }
+}
+{{end}}
+{{- if not (.HasField "Pointers")}}
+// Pointers returns pointers to the fields in Columns() order for Scan.
+{{- if .ScanNull}} Scanning NULL into a field that does not accept it
+// fails, Scan{{.ModelName}}Row and Scan{{.ModelName}}Rows handle it.
+{{- end}}
+func (m *{{.ModelName}}) Pointers() []any {
+    return []any{ {{- template "pointers" .}} }
+}
+{{end}}
+// Scan{{.ModelName}}Row reads a row selected with the columns in Columns() order.
+func Scan{{.ModelName}}Row(row *sql.Row) (*{{.ModelName}}, error) {
+    m := &{{.ModelName}}{}
+{{- template "scanNullVars" .}}
+    if err := row.Scan({{template "scanArgs" .}}); err != nil {
+        return nil, err
+    }
+{{template "scanNullAssign" .}}
+    return m, nil
+}
+
+// Scan{{.ModelName}}Rows reads all rows selected with the columns in Columns() order and closes rows.
+func Scan{{.ModelName}}Rows(rows *sql.Rows) ([]*{{.ModelName}}, error) {
+    defer rows.Close()
+
+    var models []*{{.ModelName}}
+    for rows.Next() {
+        m := &{{.ModelName}}{}
+{{- template "scanNullVars" .}}
+        if err := rows.Scan({{template "scanArgs" .}}); err != nil {
+            return nil, err
+        }
+{{template "scanNullAssign" .}}
+        models = append(models, m)
+    }
+
+    return models, rows.Err()
+}
+
+// gen:keep begin {{.ModelName}}
+// gen:keep end
+{{- define "pointers"}}{{range $i, $field := .Fields}}{{if $i}}, {{end}}&m.{{$field.Name}}{{end}}{{end}}
+{{- define "scanArgs"}}
+{{- if or .ScanNull (.HasField "Pointers")}}
+{{- range $i, $field := .Fields}}{{if $i}}, {{end}}{{if $field.ScanNull}}&null{{$field.Name}}{{else}}&m.{{$field.Name}}{{end}}{{end}}
+{{- else}}m.Pointers()...{{end}}
+{{- end}}
+{{- define "scanNullVars"}}{{range .Fields}}{{if .ScanNull}}
+    var null{{.Name}} sql.Null[{{.Type}}]
+{{- end}}{{end}}{{end}}
+{{- define "scanNullAssign"}}{{if .ScanNull}}
+{{range .Fields}}{{if .ScanNull}}    m.{{.Name}} = null{{.Name}}.V
+{{end}}{{end}}{{end}}{{end}}
 `
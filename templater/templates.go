@@ -0,0 +1,273 @@
+package templater
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/FireAnomaly/go-generator-repository/model"
+	"github.com/FireAnomaly/go-generator-repository/naming"
+)
+
+// Раскладка директории пользовательских шаблонов (Options.TemplateDir):
+//
+//	model.go.tmpl          заменяет встроенный шаблон модели, данные - TableData
+//	table/<name>.go.tmpl   генерируется для каждой таблицы в <table>_<name>.go, данные - TableData
+//	schema/<name>.go.tmpl  генерируется один раз в <name>.go, данные - SchemaData
+//
+// Имя <name> одновременно является именем генератора в Options.Generators.
+const (
+	ModelTemplateName  = "model.go.tmpl"
+	TableTemplatesDir  = "table"
+	SchemaTemplatesDir = "schema"
+	templateExt        = ".go.tmpl"
+)
+
+var ErrInvalidTemplateName = errors.New("invalid template name")
+
+// TableData данные шаблонов одной таблицы.
+type TableData struct {
+	PackageName string
+	// ImportPath путь импорта пакета модели, пуст, если Options.ImportPath не задан.
+	ImportPath string
+	// ModelName Go имя структуры модели.
+	ModelName string
+	// Table таблица целиком, включая отключенные колонки и неразобранные строки.
+	Table *model.Database
+	// Columns включенные колонки в порядке миграции, Fields соответствующие им поля структуры.
+	Columns     []model.Column
+	Fields      []Field
+	PrimaryKey  []string
+	Indexes     []model.Index
+	ForeignKeys []model.ForeignKey
+	// CustomTypes типы для ENUM колонок.
+	CustomTypes []CustomType
+	// Imports пакеты, на которые ссылаются типы полей.
+	Imports []string
+}
+
+// HasField сообщает, есть ли у модели поле name. Шаблоны не генерируют методы, имя которых занято полем.
+func (d TableData) HasField(name string) bool {
+	return slices.ContainsFunc(d.Fields, func(field Field) bool { return field.Name == name })
+}
+
+// ScanNull сообщает, что хотя бы одно поле читается через sql.Null, см. Field.ScanNull.
+func (d TableData) ScanNull() bool {
+	return slices.ContainsFunc(d.Fields, Field.ScanNull)
+}
+
+// QualifiedName имя таблицы вместе со схемой, если она указана в миграции: shop.orders.
+func (d TableData) QualifiedName() string {
+	if d.Table.TableNames.Schema == "" {
+		return d.Table.TableNames.Original
+	}
+
+	return d.Table.TableNames.Schema + "." + d.Table.TableNames.Original
+}
+
+// SchemaData данные шаблонов пакета: все его включенные таблицы. Шаблоны схемы генерируются в каждом пакете.
+type SchemaData struct {
+	PackageName string
+	ImportPath  string
+	Tables      []TableData
+}
+
+// templates шаблоны одного запуска: модель и пользовательские генераторы по имени.
+type templates struct {
+	model *template.Template
+	// builtinModel модель генерируется встроенным шаблоном, и известно, какие имена она объявляет.
+	builtinModel bool
+	table        map[string]*template.Template
+	schema       map[string]*template.Template
+}
+
+// loadTemplates разбирает встроенный шаблон модели и, если dir задана, пользовательские шаблоны из неё.
+func loadTemplates(dir string) (*templates, error) {
+	loaded := &templates{
+		builtinModel: true,
+		table:        make(map[string]*template.Template),
+		schema:       make(map[string]*template.Template),
+	}
+
+	var err error
+	loaded.model, err = template.New(ModelTemplateName).Funcs(FuncMap()).Parse(templateText)
+	if err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return loaded, nil
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, ModelTemplateName)); err == nil {
+		if loaded.model, err = parseTemplateFile(filepath.Join(dir, ModelTemplateName)); err != nil {
+			return nil, err
+		}
+
+		loaded.builtinModel = false
+	}
+
+	if loaded.table, err = parseTemplateDir(filepath.Join(dir, TableTemplatesDir)); err != nil {
+		return nil, err
+	}
+
+	if loaded.schema, err = parseTemplateDir(filepath.Join(dir, SchemaTemplatesDir)); err != nil {
+		return nil, err
+	}
+
+	// Файл <table>_model.go уже занят встроенным генератором.
+	if _, ok := loaded.table[GeneratorModels]; ok {
+		return nil, fmt.Errorf("%w: %s, override %s instead", ErrInvalidTemplateName, GeneratorModels, ModelTemplateName)
+	}
+
+	// Файлы *_ext.go принадлежат пользователю.
+	for name := range loaded.table {
+		if strings.HasSuffix("_"+name+".go", ExtFileSuffix) {
+			return nil, fmt.Errorf("%w: %s, *%s files are hand-written", ErrInvalidTemplateName, name, ExtFileSuffix)
+		}
+	}
+
+	for name := range loaded.schema {
+		if strings.HasSuffix(name+".go", ExtFileSuffix) {
+			return nil, fmt.Errorf("%w: %s, *%s files are hand-written", ErrInvalidTemplateName, name, ExtFileSuffix)
+		}
+	}
+
+	return loaded, nil
+}
+
+func parseTemplateDir(dir string) (map[string]*template.Template, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]*template.Template, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), templateExt)
+		if !reTemplateName.MatchString(name) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTemplateName, path)
+		}
+
+		if parsed[name], err = parseTemplateFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
+}
+
+var reTemplateName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func parseTemplateFile(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := template.New(filepath.Base(path)).Funcs(FuncMap()).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+
+	return parsed, nil
+}
+
+// FuncMap функции, доступные во всех шаблонах.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// Регистр и формы имён.
+		"snake":      naming.ToSnakeCase,
+		"camel":      naming.ToCamelCase,
+		"lowerCamel": naming.ToLowerCamelCase,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"plural":     Pluralize,
+		"singular":   Singularize,
+		"join":       strings.Join,
+		"quote":      strconv.Quote,
+		// comment превращает текст в строки комментария Go: {{comment .Comment}} -> // text.
+		"comment": goComment,
+		// Теги: {{tag "db" .OriginalName}} -> db:"name", {{tags "db" "id" "json" "id"}} -> db:"id" json:"id".
+		"tag":  buildTag,
+		"tags": buildTags,
+		// Проверки колонок.
+		"isEnum":       func(column model.Column) bool { return column.IsEnum() },
+		"isNullable":   func(column model.Column) bool { return column.IsNull },
+		"isPrimaryKey": func(column model.Column) bool { return column.IsPrimaryKey },
+		"isTime":       func(column model.Column) bool { return strings.Contains(column.Type, "time.Time") },
+		"isPointer":    func(column model.Column) bool { return strings.HasPrefix(column.Type, "*") },
+		"isString":     func(column model.Column) bool { return column.Type == "string" },
+		"isNumeric":    isNumeric,
+	}
+}
+
+func goComment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(line), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func buildTag(key, value string) string {
+	return key + ":" + strconv.Quote(value)
+}
+
+func buildTags(pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("tags: odd number of arguments %d", len(pairs))
+	}
+
+	tags := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		tags = append(tags, buildTag(pairs[i], pairs[i+1]))
+	}
+
+	return strings.Join(tags, " "), nil
+}
+
+var reNumericType = regexp.MustCompile(`^\*?(u?int(8|16|32|64)?|float(32|64)|uintptr|byte|rune)$`)
+
+func isNumeric(column model.Column) bool {
+	return reNumericType.MatchString(column.Type)
+}
+
+// Pluralize простое английское множественное число: user -> users, category -> categories, box -> boxes.
+func Pluralize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case word == "":
+		return word
+	case strings.HasSuffix(lower, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	default:
+		return word + "s"
+	}
+}
+
+// Singularize обратное к Pluralize: users -> user, categories -> category, boxes -> box.
+func Singularize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}